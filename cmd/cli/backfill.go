@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	monoreleaser "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var ErrBackfillUnsupported = errors.New("the provider can not backfill releases")
+
+type BackfillCommandBuilder struct {
+	releaser     monoreleaser.Releaser
+	repository   monoreleaser.Repository
+	fs           afero.Fs
+	discoverOpts monoreleaser.DiscoverOptions
+	// versionCommitMessage is the configured release.commitMessage, whose Commits are left out of the changelogs.
+	versionCommitMessage string
+	// providerOpts are the configured options of the releases at the provider, except for release.makeLatest,
+	// as backfilled releases of old tags should not become the latest release.
+	providerOpts monoreleaser.ProviderOptions
+}
+
+func (builder BackfillCommandBuilder) Build() *cobra.Command {
+	var dryRun *bool
+	var interval *time.Duration
+	var firstParent *bool
+	var makeLatest *string
+	cmd := &cobra.Command{
+		Use:   "backfill [MODULE]",
+		Short: "Create the missing provider releases of existing tags",
+		Long: `Create the missing provider releases of existing tags, oldest first with the changelog since the previous tag.
+Existing releases are skipped, so that a backfill stopped by the rate limit resumes when it is run again.
+The configured release options apply, but backfilled releases only become the latest release with --make-latest.`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backfiller, ok := builder.releaser.(monoreleaser.ReleaseBackfiller)
+			if !ok {
+				return ErrBackfillUnsupported
+			}
+
+			providerOpts := builder.providerOpts
+			mrMakeLatest, err := monoreleaser.ParseMakeLatest(*makeLatest)
+			if err != nil {
+				return err
+			}
+			providerOpts.MakeLatest = mrMakeLatest
+
+			modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+			if err != nil {
+				return err
+			}
+			if len(args) != 0 {
+				modules = []monoreleaser.Module{resolveModule(modules, args[0])}
+			}
+
+			var releases []monoreleaser.PastRelease
+			for _, module := range modules {
+				moduleReleases, err := monoreleaser.ListReleases(
+					builder.repository,
+					module,
+					monoreleaser.PastReleaseOptions{FirstParent: *firstParent, VersionCommitMessage: builder.versionCommitMessage},
+				)
+				if err != nil {
+					return fmt.Errorf("%s: %w", moduleLabel(module.Name, module.Path), err)
+				}
+				// oldest first
+				for i := len(moduleReleases) - 1; i >= 0; i-- {
+					releases = append(releases, moduleReleases[i])
+				}
+			}
+
+			created, err := monoreleaser.Backfill(backfiller, releases, monoreleaser.BackfillOptions{
+				DryRun:   *dryRun,
+				Interval: *interval,
+				Provider: providerOpts,
+			})
+			// still show what has been released so far
+			verb := "released"
+			if *dryRun {
+				verb = "would be released"
+			}
+			for _, tag := range created {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", tag.Name, verb)
+			}
+			if err != nil {
+				return fmt.Errorf("%w (run backfill again to resume)", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%d of %d tags %s\n", len(created), len(releases), verb)
+			return nil
+		},
+	}
+
+	dryRun = cmd.Flags().Bool("dry-run", false, "only list the tags without a release, without creating them")
+	interval = cmd.Flags().
+		Duration("interval", time.Second, "time to wait between creating two releases, which keeps below the secondary rate limit of GitHub")
+	firstParent = cmd.Flags().
+		Bool("first-parent", false, "only follow the first parent of merge commits, listing merged PRs instead of their commits")
+	makeLatest = cmd.Flags().
+		String("make-latest", string(monoreleaser.MakeLatestFalse), "whether the releases become the latest release: true, false or legacy")
+	return cmd
+}
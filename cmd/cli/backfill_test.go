@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/kharf/monoreleaser/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillCommand(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+
+	var created, makeLatest []string
+	rateLimited := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`[{"tag_name": "v0.1.0"}]`))
+		case http.MethodPost:
+			if rateLimited {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var release map[string]string
+			assert.NoError(t, json.Unmarshal(body, &release))
+			created = append(created, release["tag_name"])
+			makeLatest = append(makeLatest, release["make_latest"])
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1}`))
+		}
+	}))
+	defer ts.Close()
+
+	out, err := runGithubCli(t, repo, fs, ts.URL, "backfill", "--dry-run")
+	require.NoError(t, err)
+	expectedOutput := `v1.0.0 would be released
+subdir/v1.0.0 would be released
+2 of 3 tags would be released
+`
+	assert.Equal(t, expectedOutput, out)
+	assert.Empty(t, created)
+
+	rateLimited = true
+	out, err = runGithubCli(t, repo, fs, ts.URL, "backfill", "--interval", "0")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Contains(t, err.Error(), "run backfill again to resume")
+	assert.Empty(t, out)
+
+	rateLimited = false
+	out, err = runGithubCli(t, repo, fs, ts.URL, "backfill", "--interval", "0", ".")
+	require.NoError(t, err)
+	expectedOutput = `v1.0.0 released
+1 of 2 tags released
+`
+	assert.Equal(t, expectedOutput, out)
+	assert.Equal(t, []string{"v1.0.0"}, created)
+	// old tags do not become the latest release by default
+	assert.Equal(t, []string{"false"}, makeLatest)
+
+	out, err = runGithubCli(t, repo, fs, ts.URL, "backfill", "--interval", "0", "--make-latest", "legacy", "subdir")
+	require.NoError(t, err)
+	assert.Equal(t, "subdir/v1.0.0 released\n1 of 1 tags released\n", out)
+	assert.Equal(t, []string{"false", "legacy"}, makeLatest)
+
+	_, err = runGithubCli(t, repo, fs, ts.URL, "backfill", "--make-latest", "always")
+	assert.ErrorIs(t, err, ErrInvalidMakeLatest)
+}
+
+func TestBackfillCommand_Unsupported(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+	_, err := runCli(t, repo, fs, "backfill")
+	assert.ErrorIs(t, err, ErrBackfillUnsupported)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	. "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseEditCommand(t *testing.T) {
+	repo, commits, fs := newReleasesCli(t)
+	upToDate, err := GenerateChangelog(Extract(commits[9:]))
+	require.NoError(t, err)
+	regenerated, err := GenerateChangelog(Extract(commits[5:9]))
+	require.NoError(t, err)
+
+	var edited []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /repos/kharf/monoreleaser/releases/tags/v1.0.0":
+			w.Write([]byte(`{"id": 1, "body": "stale"}`))
+		case "GET /repos/kharf/monoreleaser/releases/tags/v0.1.0":
+			w.Write([]byte(`{"id": 2, "body": ` + jsonString(t, string(upToDate)) + `}`))
+		case "PATCH /repos/kharf/monoreleaser/releases/1":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			edited = append(edited, string(body))
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	run := func(args ...string) (string, error) {
+		return runGithubCli(t, repo, fs, ts.URL, append([]string{"release", "edit"}, args...)...)
+	}
+
+	out, err := run(".", "v1.0.0", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "--- v1.0.0 (current)\n+++ v1.0.0 (regenerated)\n")
+	assert.Contains(t, out, "\n-stale\n+# What's Changed\n")
+	assert.Empty(t, edited)
+
+	out, err = run("--all")
+	require.NoError(t, err)
+	expectedOutput := `v1.0.0 updated
+v0.1.0 is up to date
+subdir/v1.0.0 has no release at the provider
+`
+	assert.Equal(t, expectedOutput, out)
+	assert.Equal(t, []string{`{"body":` + jsonString(t, string(regenerated)) + `}`}, edited)
+
+	_, err = run("--all", ".")
+	assert.Error(t, err)
+}
+
+func TestReleaseEditCommand_LostEntries(t *testing.T) {
+	repo, commits, fs := newReleasesCli(t)
+	bumped, err := GenerateChangelog(append(Extract(commits[5:9]), Change{Message: "bumped dependency subdir to v1.0.0", Semantic: Patch}))
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		w.Write([]byte(`{"id": 1, "body": ` + jsonString(t, string(bumped)) + `}`))
+	}))
+	defer ts.Close()
+
+	out, err := runGithubCli(t, repo, fs, ts.URL, "release", "edit", ".", "v1.0.0")
+	require.NoError(t, err)
+	expectedOutput := `v1.0.0 is kept, as the history lacks entries of its changelog:
+  - bumped dependency subdir to v1.0.0
+`
+	assert.Equal(t, expectedOutput, out)
+}
+
+func TestReleaseEditCommand_Unsupported(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+	_, err := runCli(t, repo, fs, "release", "edit", ".", "v1.0.0")
+	assert.ErrorIs(t, err, ErrEditUnsupported)
+}
+
+// runGithubCli runs the CLI with the github provider, whose requests are sent to the test server.
+func runGithubCli(t *testing.T, repo *git.Repository, fs afero.Fs, serverURL string, args ...string) (string, error) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `owner: "kharf"
+name: "monoreleaser"
+provider: "github"
+github:
+  token: "abcd"`
+	require.NoError(t, config.ReadConfig(bytes.NewBufferString(configYaml)))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+	serverUrl, err := url.Parse(serverURL)
+	require.NoError(t, err)
+	releaser := rootCmdBuilder.releaseCmdBuilder.releaser.(*GithubReleaser)
+	releaser.ReleaseClient().URL().Host = serverUrl.Host
+	releaser.ReleaseClient().URL().Scheme = serverUrl.Scheme
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(args)
+	_, err = rootCmd.ExecuteC()
+	return buffer.String(), err
+}
+
+func jsonString(t *testing.T, value string) string {
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	return string(encoded)
+}
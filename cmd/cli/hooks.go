@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	monoreleaser "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type HooksCommandBuilder struct {
+	// repository is the repository discovered in main, whose hooks are installed.
+	repository *git.Repository
+	fs         afero.Fs
+	lintOpts   monoreleaser.LintOptions
+	// scopes are the names of the configured Modules, which are suggested by the prepare-commit-msg template.
+	scopes []string
+}
+
+func (builder HooksCommandBuilder) Build() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage the git hooks of the repository",
+	}
+
+	var prepareCommitMsg *bool
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install a commit-msg hook linting commit messages",
+		Long: `Install a commit-msg hook running lint --commit-msg into .git/hooks or core.hooksPath.
+With --prepare-commit-msg, install a hook adding a message template, which lists the allowed types and the module names as scopes.
+Reinstalling is idempotent, existing hooks not installed by monoreleaser are left untouched.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			dir, err := monoreleaser.HooksDir(builder.repository)
+			if err != nil {
+				return err
+			}
+
+			hooks, err := monoreleaser.InstallHooks(builder.fs, dir, monoreleaser.HookOptions{
+				PrepareCommitMsg: *prepareCommitMsg,
+				Types:            builder.lintOpts.Types,
+				Scopes:           builder.scopes,
+			})
+			if err != nil {
+				return err
+			}
+
+			var skipped []string
+			for _, hook := range hooks {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", hook.Path, hook.Status)
+				if hook.Status == monoreleaser.HookSkipped {
+					skipped = append(skipped, hook.Name)
+				}
+			}
+
+			if len(skipped) != 0 {
+				return fmt.Errorf("%w: %s, call monoreleaser from it yourself", monoreleaser.ErrHookExists, strings.Join(skipped, ", "))
+			}
+			return nil
+		},
+	}
+	prepareCommitMsg = installCmd.Flags().
+		Bool("prepare-commit-msg", false, "also install a prepare-commit-msg hook adding a template of the allowed types and scopes to the message")
+
+	cmd.AddCommand(installCmd)
+	return cmd
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooksInstallCommand(t *testing.T) {
+	// no global core.hooksPath of the machine running the tests
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	configYaml := `name: "monoreleaser"
+lint:
+  types: ["feat", "fix"]
+modules:
+  - name: api
+    paths: [subdir]`
+	config := viper.New()
+	config.SetConfigType("yaml")
+	require.NoError(t, config.ReadConfig(bytes.NewBufferString(configYaml)))
+
+	repo, _ := newDiskRepo(t, false)
+	workTree, err := repo.Worktree()
+	require.NoError(t, err)
+	hooksDir := filepath.Join(workTree.Filesystem.Root(), ".git", "hooks")
+
+	install := func(args ...string) (string, error) {
+		rootCmdBuilder, err := initCli(repo, config, afero.NewOsFs())
+		require.NoError(t, err)
+		rootCmd := rootCmdBuilder.Build()
+		out := &bytes.Buffer{}
+		rootCmd.SetOut(out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"hooks", "install"}, args...))
+		_, err = rootCmd.ExecuteC()
+		return out.String(), err
+	}
+
+	out, err := install("--prepare-commit-msg")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%s installed\n%s installed\n",
+		filepath.Join(hooksDir, "commit-msg"),
+		filepath.Join(hooksDir, "prepare-commit-msg"),
+	), out)
+
+	script, err := os.ReadFile(filepath.Join(hooksDir, "prepare-commit-msg"))
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "# types: feat, fix\n# scopes: api'")
+
+	out, err = install()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(hooksDir, "commit-msg")+" unchanged\n", out)
+
+	existing := []byte("#!/bin/sh\nexit 0\n")
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "commit-msg"), existing, 0o755))
+	out, err = install()
+	assert.ErrorIs(t, err, ErrHookExists)
+	assert.Equal(t, filepath.Join(hooksDir, "commit-msg")+" skipped\n", out)
+
+	script, err = os.ReadFile(filepath.Join(hooksDir, "commit-msg"))
+	require.NoError(t, err)
+	assert.Equal(t, existing, script)
+}
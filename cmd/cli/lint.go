@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	monoreleaser "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrLintFailed          = errors.New("commit messages do not follow Conventional Commits")
+	ErrUnknownOutputFormat = errors.New("unknown output format")
+)
+
+type LintCommandBuilder struct {
+	repository monoreleaser.Repository
+	fs         afero.Fs
+	lintOpts   monoreleaser.LintOptions
+}
+
+func (builder LintCommandBuilder) Build() *cobra.Command {
+	var from *string
+	var to *string
+	var format *string
+	var commitMsg *string
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Lint commit messages against Conventional Commits",
+		Long: `Lint the messages of the commits between two revisions against Conventional Commits and the configured types.
+As a commit-msg hook, lint the message of the commit about to be created with --commit-msg.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var issues []monoreleaser.LintIssue
+			if *commitMsg != "" {
+				content, err := afero.ReadFile(builder.fs, *commitMsg)
+				if err != nil {
+					return err
+				}
+				issues = monoreleaser.LintMessage(monoreleaser.CleanMessage(string(content)), builder.lintOpts)
+			} else {
+				var err error
+				issues, err = monoreleaser.LintRange(builder.repository, *from, *to, builder.lintOpts)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := writeLintIssues(cmd.OutOrStdout(), *format, *commitMsg, issues); err != nil {
+				return err
+			}
+
+			if len(issues) != 0 {
+				return fmt.Errorf("%w: %d issue(s)", ErrLintFailed, len(issues))
+			}
+			return nil
+		},
+	}
+
+	from = cmd.Flags().String("from", "", "lint the commits after this revision (default: the whole history)")
+	to = cmd.Flags().String("to", "HEAD", "lint the commits up to this revision")
+	format = cmd.Flags().String("format", "text", "output format: text, json or github (workflow command annotations)")
+	commitMsg = cmd.Flags().String("commit-msg", "", "lint the message in this file, like the commit-msg hook passes it")
+	return cmd
+}
+
+// writeLintIssues writes the issues in the given format.
+// The file is the commit message file in hook mode, which issues without a Commit refer to.
+func writeLintIssues(out io.Writer, format string, file string, issues []monoreleaser.LintIssue) error {
+	switch format {
+	case "text":
+		for _, issue := range issues {
+			fmt.Fprintf(out, "%s %q: line %d: %s\n", lintLabel(issue, file), issue.Subject, issue.Line, issue.Message)
+		}
+		if len(issues) == 0 {
+			fmt.Fprintln(out, "no issues found")
+		}
+		return nil
+	case "json":
+		if issues == nil {
+			issues = []monoreleaser.LintIssue{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(issues)
+	case "github":
+		for _, issue := range issues {
+			properties := "title=" + escapeAnnotationProperty("Commit "+lintLabel(issue, file))
+			if issue.Hash == "" && file != "" {
+				properties = fmt.Sprintf("file=%s,line=%d,%s", escapeAnnotationProperty(file), issue.Line, properties)
+			}
+			fmt.Fprintf(out, "::error %s::%s\n", properties, escapeAnnotationData(issue.Subject+": "+issue.Message))
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s (expected text, json or github)", ErrUnknownOutputFormat, format)
+	}
+}
+
+// lintLabel returns the abbreviated hash of the linted Commit or the name of the commit message file.
+func lintLabel(issue monoreleaser.LintIssue, file string) string {
+	switch {
+	case issue.Hash != "":
+		return shortHash(issue.Hash)
+	case file != "":
+		return file
+	default:
+		return "message"
+	}
+}
+
+// escapeAnnotationData escapes the message of a GitHub Actions workflow command.
+func escapeAnnotationData(data string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(data)
+}
+
+// escapeAnnotationProperty escapes a property value of a GitHub Actions workflow command.
+func escapeAnnotationProperty(property string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(property)
+}
+
+// initLintOptions reads the allowed commit types and the maximum header length from the lint key.
+func initLintOptions(config *viper.Viper) (monoreleaser.LintOptions, error) {
+	types, err := monoreleaser.ParseTypes(config.GetStringSlice("lint.types"))
+	if err != nil {
+		return monoreleaser.LintOptions{}, err
+	}
+
+	return monoreleaser.LintOptions{
+		Types:           types,
+		MaxHeaderLength: config.GetInt("lint.maxHeaderLength"),
+	}, nil
+}
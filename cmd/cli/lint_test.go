@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	. "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLintCli(t *testing.T, configYaml string, fs afero.Fs) (*cobra.Command, *bytes.Buffer) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, _ := newRepo(false)
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	rootCmd := rootCmdBuilder.Build()
+	out := &bytes.Buffer{}
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	return rootCmd, out
+}
+
+func TestLintCommand(t *testing.T) {
+	rootCmd, out := newLintCli(t, `name: "monoreleaser"`, afero.NewMemMapFs())
+	rootCmd.SetArgs([]string{"lint"})
+
+	_, err := rootCmd.ExecuteC()
+	require.NoError(t, err)
+	assert.Equal(t, "no issues found\n", out.String())
+}
+
+func TestLintCommand_ConfiguredTypes(t *testing.T) {
+	configYaml := `name: "monoreleaser"
+lint:
+  types: ["feat", "fix", "docs"]`
+	rootCmd, out := newLintCli(t, configYaml, afero.NewMemMapFs())
+	rootCmd.SetArgs([]string{"lint", "--from", "HEAD~2", "--format", "json"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrLintFailed)
+
+	var issues []LintIssue
+	require.NoError(t, json.Unmarshal(out.Bytes(), &issues))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "build: change", issues[0].Subject)
+	assert.Equal(t, `type "build" is not one of feat, fix, docs`, issues[0].Message)
+}
+
+func TestLintCommand_UnknownConfiguredType(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	require.NoError(t, config.ReadConfig(bytes.NewBufferString("name: \"monoreleaser\"\nlint:\n  types: [\"feat\", \"security\"]")))
+
+	repo, _ := newRepo(false)
+	_, err := initCli(repo, config, afero.NewMemMapFs())
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
+
+func TestLintCommand_CommitMsg(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	message := "added login\n# Please enter the commit message for your changes.\n"
+	require.NoError(t, afero.WriteFile(fs, ".git/COMMIT_EDITMSG", []byte(message), 0o644))
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   "text",
+			expected: ".git/COMMIT_EDITMSG \"added login\": line 1: header must look like type(scope)!: description\n",
+		},
+		{
+			format:   "github",
+			expected: "::error file=.git/COMMIT_EDITMSG,line=1,title=Commit .git/COMMIT_EDITMSG::added login: header must look like type(scope)!: description\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		rootCmd, out := newLintCli(t, `name: "monoreleaser"`, fs)
+		rootCmd.SetArgs([]string{"lint", "--commit-msg", ".git/COMMIT_EDITMSG", "--format", testCase.format})
+
+		_, err := rootCmd.ExecuteC()
+		assert.ErrorIs(t, err, ErrLintFailed)
+		assert.Equal(t, testCase.expected, out.String())
+	}
+
+	require.NoError(t, afero.WriteFile(fs, ".git/COMMIT_EDITMSG", []byte("feat: add login\n# comment\n"), 0o644))
+	rootCmd, _ := newLintCli(t, `name: "monoreleaser"`, fs)
+	rootCmd.SetArgs([]string{"lint", "--commit-msg", ".git/COMMIT_EDITMSG"})
+	_, err := rootCmd.ExecuteC()
+	assert.NoError(t, err)
+}
+
+func TestLintCommand_UnknownFormat(t *testing.T) {
+	rootCmd, _ := newLintCli(t, `name: "monoreleaser"`, afero.NewMemMapFs())
+	rootCmd.SetArgs([]string{"lint", "--format", "xml"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrUnknownOutputFormat)
+}
+
+func TestEscapeAnnotation(t *testing.T) {
+	assert.Equal(t, "100%25%0Adone", escapeAnnotationData("100%\ndone"))
+	assert.Equal(t, "a%3Ab%2Cc", escapeAnnotationProperty("a:b,c"))
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/go-git/go-git/v5"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	monoreleaser "github.com/kharf/monoreleaser/internal"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -14,7 +18,15 @@ import (
 )
 
 type RootCommandBuilder struct {
-	releaseCmdBuilder ReleaseCommandBuilder
+	releaseCmdBuilder  ReleaseCommandBuilder
+	modulesCmdBuilder  ModulesCommandBuilder
+	lintCmdBuilder     LintCommandBuilder
+	hooksCmdBuilder    HooksCommandBuilder
+	statusCmdBuilder   StatusCommandBuilder
+	releasesCmdBuilder ReleasesCommandBuilder
+	showCmdBuilder     ShowCommandBuilder
+	backfillCmdBuilder BackfillCommandBuilder
+	publishCmdBuilder  PublishCommandBuilder
 }
 
 func (builder RootCommandBuilder) Build() *cobra.Command {
@@ -28,29 +40,116 @@ It aims to support a variety of Languages, Repository structures and Git hosting
 	releaseCmd := builder.releaseCmdBuilder.Build()
 	rootCmd.AddCommand(releaseCmd)
 
+	modulesCmd := builder.modulesCmdBuilder.Build()
+	rootCmd.AddCommand(modulesCmd)
+
+	lintCmd := builder.lintCmdBuilder.Build()
+	rootCmd.AddCommand(lintCmd)
+
+	hooksCmd := builder.hooksCmdBuilder.Build()
+	rootCmd.AddCommand(hooksCmd)
+
+	statusCmd := builder.statusCmdBuilder.Build()
+	rootCmd.AddCommand(statusCmd)
+
+	releasesCmd := builder.releasesCmdBuilder.Build()
+	rootCmd.AddCommand(releasesCmd)
+
+	showCmd := builder.showCmdBuilder.Build()
+	rootCmd.AddCommand(showCmd)
+
+	backfillCmd := builder.backfillCmdBuilder.Build()
+	rootCmd.AddCommand(backfillCmd)
+
+	publishCmd := builder.publishCmdBuilder.Build()
+	rootCmd.AddCommand(publishCmd)
+
 	return &rootCmd
 }
 
 type ReleaseCommandBuilder struct {
-	releaser monoreleaser.Releaser
-	fs       afero.Fs
+	releaser     monoreleaser.Releaser
+	repository   monoreleaser.Repository
+	fs           afero.Fs
+	discoverOpts monoreleaser.DiscoverOptions
+	bumpOpts     monoreleaser.BumpOptions
+	// helmRepository is the chart repository Helm charts are published to, or nil if none is configured.
+	helmRepository *monoreleaser.HelmRepository
+	versionPolicy  monoreleaser.VersionPolicy
+	// providerOpts are the configured options of the releases at the provider, which the flags override.
+	providerOpts monoreleaser.ProviderOptions
 }
 
 func (builder ReleaseCommandBuilder) Build() *cobra.Command {
 	var artifacts *[]string
+	var firstParent *bool
+	var all *bool
+	var draft *bool
+	var prerelease *bool
+	var makeLatest *string
+	var targetCommitish *string
+	var discussionCategory *string
+	var nameTemplate *string
 	cmd := &cobra.Command{
 		Use:   "release [MODULE] [VERSION]",
 		Short: "Release a piece of Software (Module)",
-		Args:  cobra.MinimumNArgs(2),
-		RunE: func(_ *cobra.Command, args []string) error {
-			var module string
-			var moduleDir string
-			if args[0] == "." {
-				module = ""
-				moduleDir = ""
-			} else {
-				module = args[0]
-				moduleDir = module + "/"
+		Args: func(cmd *cobra.Command, args []string) error {
+			if *all {
+				return cobra.NoArgs(cmd, args)
+			}
+			// the version of Modules with a file or commits version source is optional
+			if len(args) != 0 {
+				switch builder.resolveModule(args[0]).VersionSource {
+				case monoreleaser.VersionSourceFile, monoreleaser.VersionSourceCommits:
+					return nil
+				}
+			}
+			return cobra.MinimumNArgs(2)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			providerOpts := builder.providerOpts
+			flags := cmd.Flags()
+			if flags.Changed("draft") {
+				providerOpts.Draft = *draft
+			}
+			if flags.Changed("prerelease") {
+				providerOpts.Prerelease = *prerelease
+			}
+			if flags.Changed("make-latest") {
+				mrMakeLatest, err := monoreleaser.ParseMakeLatest(*makeLatest)
+				if err != nil {
+					return err
+				}
+				providerOpts.MakeLatest = mrMakeLatest
+			}
+			if flags.Changed("target-commitish") {
+				providerOpts.TargetCommitish = *targetCommitish
+			}
+			if flags.Changed("discussion-category") {
+				providerOpts.DiscussionCategoryName = *discussionCategory
+			}
+			if flags.Changed("name-template") {
+				mrNameTemplate, err := monoreleaser.NewReleaseNameTemplate(*nameTemplate)
+				if err != nil {
+					return err
+				}
+				providerOpts.NameTemplate = mrNameTemplate
+			}
+
+			if *all {
+				return builder.releaseAll(cmd.OutOrStdout(), *firstParent, providerOpts)
+			}
+
+			module := builder.resolveModule(args[0])
+			moduleDir := moduleDir(module.Path)
+
+			version, err := builder.resolveVersion(module, args[1:], *firstParent)
+			if err != nil {
+				return err
+			}
+			if version == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s has nothing to release\n", moduleLabel(module.Name, module.Path))
+				return nil
 			}
 
 			var mrArtifacts []monoreleaser.Artifact
@@ -69,25 +168,350 @@ func (builder ReleaseCommandBuilder) Build() *cobra.Command {
 				mrArtifacts = append(
 					mrArtifacts,
 					monoreleaser.Artifact{
-						Reader: bufio.NewReader(file),
+						// files are read again from the start, when the upload is retried
+						Reader: file,
 						Name:   artifactName,
 						Size:   fileStat.Size(),
 					},
 				)
 			}
 
-			return builder.releaser.Release(args[1], monoreleaser.ReleaseOptions{
-				Module:    module,
-				Artifacts: mrArtifacts,
+			// configured names are never resolvable by go get, so that only Modules tagged by their directory are validated
+			tagModule := module.TagModule
+			if !taggedByName(module.Name, module.Path) {
+				goTagModule, err := monoreleaser.ValidateGoRelease(builder.fs, builder.discoverOpts.Root, module.Path, version)
+				if err != nil {
+					return err
+				}
+				tagModule = goTagModule
+			}
+
+			return builder.releaseModule(module, version, monoreleaser.ReleaseOptions{
+				Module:      module.Path,
+				TagModule:   tagModule,
+				Paths:       module.Paths,
+				Artifacts:   mrArtifacts,
+				FirstParent: *firstParent,
+				Provider:    providerOpts,
 			})
 		},
 	}
 
 	artifacts = cmd.Flags().
 		StringSlice("artifacts", []string{}, "artifacts to upload alongside the changelog (if supported by the provider)")
+	firstParent = cmd.Flags().
+		Bool("first-parent", false, "only follow the first parent of merge commits, listing merged PRs instead of their commits")
+	all = cmd.Flags().
+		Bool("all", false, "release every module with changes since its latest release, deriving the versions from the commits")
+	draft = cmd.Flags().
+		Bool("draft", false, "create the release as a draft, which is published later (if supported by the provider)")
+	prerelease = cmd.Flags().
+		Bool("prerelease", false, "mark the release as a pre-release, which pre-release versions like v1.0.0-rc.1 always are")
+	makeLatest = cmd.Flags().
+		String("make-latest", "", "whether the release becomes the latest release: true, false or legacy (if supported by the provider)")
+	targetCommitish = cmd.Flags().
+		String("target-commitish", "", "branch or commit the provider creates the tag from, if it does not exist yet")
+	discussionCategory = cmd.Flags().
+		String("discussion-category", "", "category of the discussion started for the release (if supported by the provider)")
+	nameTemplate = cmd.Flags().
+		String("name-template", "", "template of the release name, e.g. \"{{.Module}} {{.Version}}\" (defaults to the tag name)")
+
+	cmd.AddCommand(builder.buildEdit())
 	return cmd
 }
 
+func (builder ReleaseCommandBuilder) buildEdit() *cobra.Command {
+	var all *bool
+	var dryRun *bool
+	var firstParent *bool
+	cmd := &cobra.Command{
+		Use:   "edit [MODULE VERSION]",
+		Short: "Regenerate the changelog of existing releases and update them at the provider",
+		Long: `Regenerate the changelog of an existing release, or of all releases with --all, from the history and update it at the provider.
+Version bump commits are left out like on release.
+Releases whose notes have entries the history lacks, like bumped dependencies, are kept.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if *all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			editor, ok := builder.releaser.(monoreleaser.ReleaseEditor)
+			if !ok {
+				return ErrEditUnsupported
+			}
+
+			modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+			if err != nil {
+				return err
+			}
+
+			pastReleaseOpts := monoreleaser.PastReleaseOptions{
+				FirstParent:          *firstParent,
+				VersionCommitMessage: builder.bumpOpts.Message,
+			}
+			var releases []monoreleaser.PastRelease
+			if *all {
+				for _, module := range modules {
+					moduleReleases, err := monoreleaser.ListReleases(builder.repository, module, pastReleaseOpts)
+					if err != nil {
+						return fmt.Errorf("%s: %w", moduleLabel(module.Name, module.Path), err)
+					}
+					releases = append(releases, moduleReleases...)
+				}
+			} else {
+				release, _, err := monoreleaser.ShowRelease(builder.repository, resolveModule(modules, args[0]), args[1], pastReleaseOpts)
+				if err != nil {
+					return err
+				}
+				releases = append(releases, *release)
+			}
+
+			edits, err := monoreleaser.EditChangelogs(editor, releases, monoreleaser.EditOptions{DryRun: *dryRun})
+			// still show what has been edited so far
+			for _, edit := range edits {
+				if writeErr := writeEdit(cmd.OutOrStdout(), edit, *dryRun); writeErr != nil {
+					return writeErr
+				}
+			}
+			return err
+		},
+	}
+
+	all = cmd.Flags().Bool("all", false, "regenerate the changelog of every release of every module")
+	dryRun = cmd.Flags().Bool("dry-run", false, "only print the diff of the changelogs, without updating them")
+	firstParent = cmd.Flags().
+		Bool("first-parent", false, "only follow the first parent of merge commits, listing merged PRs instead of their commits")
+	return cmd
+}
+
+// writeEdit reports the outcome of a ChangelogEdit, which is the diff of the changelogs in dry-run mode.
+func writeEdit(out io.Writer, edit monoreleaser.ChangelogEdit, dryRun bool) error {
+	switch {
+	case edit.Missing:
+		fmt.Fprintf(out, "%s has no release at the provider\n", edit.Tag.Name)
+	case len(edit.Lost) != 0:
+		fmt.Fprintf(out, "%s is kept, as the history lacks entries of its changelog:\n", edit.Tag.Name)
+		for _, entry := range edit.Lost {
+			fmt.Fprintf(out, "  - %s\n", entry)
+		}
+	case !edit.Changed():
+		fmt.Fprintf(out, "%s is up to date\n", edit.Tag.Name)
+	case dryRun:
+		diff, err := edit.Diff()
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, diff)
+	default:
+		fmt.Fprintf(out, "%s updated\n", edit.Tag.Name)
+	}
+	return nil
+}
+
+// resolveModule returns the Module passed on the command line,
+// which is either the name of a configured Module or a directory, where "." is the repository root.
+func (builder ReleaseCommandBuilder) resolveModule(arg string) monoreleaser.Module {
+	return resolveModule(builder.discoverOpts.Modules, arg)
+}
+
+// resolveModule returns the Module of the given Modules named by arg, or the directory arg refers to.
+func resolveModule(modules []monoreleaser.Module, arg string) monoreleaser.Module {
+	for _, module := range modules {
+		if module.Name == arg {
+			return module
+		}
+	}
+
+	if arg == "." {
+		return monoreleaser.Module{}
+	}
+	return monoreleaser.Module{Path: arg}
+}
+
+// resolveVersion returns the version a Module is released with, which is either passed on the command line or read from its VersionSource.
+// An empty version means, that the Module has nothing to release.
+func (builder ReleaseCommandBuilder) resolveVersion(module monoreleaser.Module, args []string, firstParent bool) (string, error) {
+	source := module.VersionSource
+	if source != monoreleaser.VersionSourceFile && source != monoreleaser.VersionSourceCommits {
+		return args[0], nil
+	}
+
+	latestTag, commits, err := monoreleaser.Unreleased(builder.repository, module, monoreleaser.DiffOptions{FirstParent: firstParent})
+	if err != nil {
+		return "", err
+	}
+
+	var version string
+	switch {
+	case len(args) != 0:
+		version = args[0]
+	case source == monoreleaser.VersionSourceFile:
+		version, err = monoreleaser.ReadVersionFile(builder.fs, builder.discoverOpts.Root, module)
+		if err != nil {
+			return "", err
+		}
+		// the version has not been raised since the latest release
+		if latestTag != nil && monoreleaser.SameVersion(latestTag.Version, version) {
+			return "", nil
+		}
+	default:
+		var latestVersion string
+		if latestTag != nil {
+			latestVersion = latestTag.Version
+		}
+		var changed bool
+		version, changed, err = monoreleaser.NextVersion(latestVersion, monoreleaser.Extract(commits))
+		if err != nil || !changed {
+			return "", err
+		}
+	}
+
+	return version, monoreleaser.ValidateNextVersion(latestTag, version)
+}
+
+// moduleDir returns the prefix of the files inside of a Module directory.
+func moduleDir(module string) string {
+	if module == "" {
+		return ""
+	}
+	return module + "/"
+}
+
+// releaseAll releases every discovered Module with unreleased Changes and prints a summary of the releases.
+func (builder ReleaseCommandBuilder) releaseAll(out io.Writer, firstParent bool, providerOpts monoreleaser.ProviderOptions) error {
+	modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+	if err != nil {
+		return err
+	}
+
+	graph, err := monoreleaser.BuildDependencyGraph(builder.fs, builder.discoverOpts.Root, modules)
+	if err != nil {
+		return err
+	}
+
+	plan, err := monoreleaser.PlanReleases(builder.repository, modules, monoreleaser.DiffOptions{FirstParent: firstParent})
+	if err != nil {
+		return err
+	}
+
+	filePlan, err := monoreleaser.PlanFileReleases(builder.fs, builder.discoverOpts.Root, builder.repository, modules, monoreleaser.DiffOptions{FirstParent: firstParent})
+	if err != nil {
+		return err
+	}
+	plan = append(plan, filePlan...)
+
+	plan, err = monoreleaser.CascadeReleases(builder.repository, modules, plan, graph)
+	if err != nil {
+		return err
+	}
+
+	// refuse the whole plan, before anything was released
+	for i := range plan {
+		opts := plannedReleaseOptions(plan[i], firstParent)
+		opts.VersionPolicy = builder.versionPolicy
+		if err := monoreleaser.ValidateRelease(builder.repository, plan[i].Version, opts); err != nil {
+			return fmt.Errorf("%s: %w", moduleLabel(plan[i].Name, plan[i].Module), err)
+		}
+
+		if taggedByName(plan[i].Name, plan[i].Module) {
+			continue
+		}
+		if _, err := monoreleaser.ValidateGoRelease(builder.fs, builder.discoverOpts.Root, plan[i].Module, plan[i].Version); err != nil {
+			return err
+		}
+	}
+
+	if len(plan) == 0 {
+		fmt.Fprintln(out, "no module has unreleased changes")
+		return nil
+	}
+
+	modulesByPath := make(map[string]monoreleaser.Module, len(modules))
+	for _, module := range modules {
+		modulesByPath[module.Path] = module
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "MODULE\tPREVIOUS\tVERSION\tCHANGES")
+	for _, release := range plan {
+		opts := plannedReleaseOptions(release, firstParent)
+		opts.Provider = providerOpts
+		err := builder.releaseModule(modulesByPath[release.Module], release.Version, opts)
+		if err != nil {
+			// still show what has been released so far
+			writer.Flush()
+			return fmt.Errorf("%s: %w", moduleLabel(release.Name, release.Module), err)
+		}
+
+		previous := "-"
+		if release.LatestTag != nil {
+			previous = release.LatestTag.Name
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\n", moduleLabel(release.Name, release.Module), previous, release.Version, len(release.Changes)+len(release.DependencyBumps))
+	}
+
+	return writer.Flush()
+}
+
+// plannedReleaseOptions returns the options a planned release is released with.
+func plannedReleaseOptions(release monoreleaser.PlannedRelease, firstParent bool) monoreleaser.ReleaseOptions {
+	return monoreleaser.ReleaseOptions{
+		Module:      release.Module,
+		TagModule:   release.TagModule,
+		Paths:       release.Paths,
+		FirstParent: firstParent,
+		Changes:     release.DependencyBumps,
+	}
+}
+
+// releaseModule validates the version, bumps the version files of a Module, if it has any, and releases it.
+// Helm charts are packaged and attached to the release, and published to the chart repository, if one is configured.
+func (builder ReleaseCommandBuilder) releaseModule(
+	module monoreleaser.Module,
+	version string,
+	opts monoreleaser.ReleaseOptions,
+) error {
+	// the version files are not bumped for a version, which the releaser refuses
+	opts.VersionPolicy = builder.versionPolicy
+	if err := monoreleaser.ValidateRelease(builder.repository, version, opts); err != nil {
+		return err
+	}
+
+	module.VersionFiles = monoreleaser.ChartVersionFiles(module)
+	versionCommit, err := monoreleaser.BumpVersionFiles(builder.fs, builder.repository, module, version, builder.bumpOpts)
+	if err != nil {
+		return err
+	}
+	opts.VersionCommit = versionCommit
+
+	var chart *monoreleaser.ChartPackage
+	if module.Chart {
+		dir := filepath.Join(builder.discoverOpts.Root, module.Path)
+		if dir == "" {
+			dir = "."
+		}
+		chart, err = monoreleaser.PackageChart(builder.fs, dir)
+		if err != nil {
+			return err
+		}
+		opts.Artifacts = append(opts.Artifacts, chart.Artifact())
+	}
+
+	if err := builder.releaser.Release(version, opts); err != nil {
+		return err
+	}
+
+	if chart == nil || builder.helmRepository == nil {
+		return nil
+	}
+	_, err = monoreleaser.PublishChart(builder.fs, builder.repository, *builder.helmRepository, chart, monoreleaser.PublishChartOptions{
+		Root: builder.discoverOpts.Root,
+	})
+	return err
+}
+
 func main() {
 	repository, err := git.PlainOpen(".")
 	if err != nil {
@@ -109,7 +533,8 @@ func main() {
 	err = rootCmdBuilder.Build().Execute()
 	if err != nil {
 		fmt.Println(err)
-		return
+		// e.g. failed lints have to fail hooks and pipelines
+		os.Exit(1)
 	}
 }
 
@@ -117,6 +542,8 @@ type PlaceholderReleaser struct{}
 
 var _ monoreleaser.Releaser = PlaceholderReleaser{}
 var ErrUnimplemented = errors.New("implement me daddy")
+var ErrUnknownBackend = errors.New("unknown git backend")
+var ErrEditUnsupported = errors.New("the provider can not edit releases")
 
 func (rel PlaceholderReleaser) Release(version string, opts monoreleaser.ReleaseOptions) error {
 	var _ = version
@@ -153,12 +580,14 @@ func initCli(
 	config.SetDefault("timeout", 10)
 	token := config.GetString("github.token")
 
-	gitRepository := monoreleaser.NewGoGitRepository(name, repository)
+	gitRepository, err := initRepository(name, repository, config, token)
+	if err != nil {
+		return nil, err
+	}
 
 	var releaser monoreleaser.Releaser
 	if provider == "github" {
-		var err error
-		releaser, err = monoreleaser.NewGithubReleaser(
+		githubReleaser, err := monoreleaser.NewGithubReleaser(
 			owner,
 			gitRepository,
 			timeout,
@@ -167,12 +596,252 @@ func initCli(
 		if err != nil {
 			return nil, err
 		}
+		releaser = githubReleaser.WithRetryPolicy(initRetryPolicy(config))
 	} else {
 		releaser = PlaceholderReleaser{}
 	}
 
-	releaseCmd := ReleaseCommandBuilder{releaser: releaser, fs: fs}
-	rootCmd := RootCommandBuilder{releaseCmd}
+	modules, err := initModules(config)
+	if err != nil {
+		return nil, err
+	}
+
+	versionPolicy, err := monoreleaser.ParseVersionPolicy(config.GetString("release.versionPolicy"))
+	if err != nil {
+		return nil, err
+	}
+
+	providerOpts, err := initProviderOptions(config)
+	if err != nil {
+		return nil, err
+	}
+
+	discoverOpts := monoreleaser.DiscoverOptions{
+		Markers: config.GetStringSlice("discovery.markers"),
+		Globs:   config.GetStringSlice("discovery.globs"),
+		Modules: modules,
+	}
+	releaseCmd := ReleaseCommandBuilder{
+		releaser:     releaser,
+		repository:   gitRepository,
+		fs:           fs,
+		discoverOpts: discoverOpts,
+		bumpOpts: monoreleaser.BumpOptions{
+			Message: config.GetString("release.commitMessage"),
+		},
+		helmRepository: initHelmRepository(config),
+		versionPolicy:  versionPolicy,
+		providerOpts:   providerOpts,
+	}
+	modulesCmd := ModulesCommandBuilder{
+		repository:   gitRepository,
+		fs:           fs,
+		discoverOpts: discoverOpts,
+	}
+	lintOpts, err := initLintOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	lintCmd := LintCommandBuilder{
+		repository: gitRepository,
+		fs:         fs,
+		lintOpts:   lintOpts,
+	}
+	var scopes []string
+	for _, module := range modules {
+		if module.Name != "" {
+			scopes = append(scopes, module.Name)
+		}
+	}
+	hooksCmd := HooksCommandBuilder{
+		repository: repository,
+		fs:         fs,
+		lintOpts:   lintOpts,
+		scopes:     scopes,
+	}
+	statusCmd := StatusCommandBuilder{
+		repository:   gitRepository,
+		fs:           fs,
+		discoverOpts: discoverOpts,
+	}
+	releasesCmd := ReleasesCommandBuilder{
+		repository:           gitRepository,
+		fs:                   fs,
+		discoverOpts:         discoverOpts,
+		versionCommitMessage: config.GetString("release.commitMessage"),
+	}
+	showCmd := ShowCommandBuilder{
+		repository:           gitRepository,
+		fs:                   fs,
+		discoverOpts:         discoverOpts,
+		versionCommitMessage: config.GetString("release.commitMessage"),
+	}
+	backfillCmd := BackfillCommandBuilder{
+		releaser:             releaser,
+		repository:           gitRepository,
+		fs:                   fs,
+		discoverOpts:         discoverOpts,
+		versionCommitMessage: config.GetString("release.commitMessage"),
+		providerOpts:         providerOpts,
+	}
+	publishCmd := PublishCommandBuilder{
+		releaser:     releaser,
+		repository:   gitRepository,
+		fs:           fs,
+		discoverOpts: discoverOpts,
+		assets:       config.GetStringSlice("publish.assets"),
+		makeLatest:   providerOpts.MakeLatest,
+	}
+	rootCmd := RootCommandBuilder{
+		releaseCmdBuilder:  releaseCmd,
+		modulesCmdBuilder:  modulesCmd,
+		lintCmdBuilder:     lintCmd,
+		hooksCmdBuilder:    hooksCmd,
+		statusCmdBuilder:   statusCmd,
+		releasesCmdBuilder: releasesCmd,
+		showCmdBuilder:     showCmd,
+		backfillCmdBuilder: backfillCmd,
+		publishCmdBuilder:  publishCmd,
+	}
 
 	return &rootCmd, nil
 }
+
+// initRetryPolicy reads the retries of the requests to the provider from the http key, which default to monoreleaser.DefaultRetryPolicy.
+// http.maxRetries limits the retries and http.maxWait the wait for a rate limit to reset, longer waits fail right away.
+func initRetryPolicy(config *viper.Viper) monoreleaser.RetryPolicy {
+	policy := monoreleaser.DefaultRetryPolicy
+	if config.IsSet("http.maxRetries") {
+		policy.MaxRetries = config.GetInt("http.maxRetries")
+	}
+	if config.IsSet("http.maxWait") {
+		policy.MaxWait = config.GetDuration("http.maxWait")
+	}
+	return policy
+}
+
+// initProviderOptions reads the options of the releases at the provider from the release key.
+// release.draft, release.prerelease, release.makeLatest, release.targetCommitish, release.discussionCategory and release.nameTemplate
+// are the defaults of the corresponding flags of the release command.
+func initProviderOptions(config *viper.Viper) (monoreleaser.ProviderOptions, error) {
+	makeLatest, err := monoreleaser.ParseMakeLatest(config.GetString("release.makeLatest"))
+	if err != nil {
+		return monoreleaser.ProviderOptions{}, err
+	}
+
+	providerOpts := monoreleaser.ProviderOptions{
+		Draft:                  config.GetBool("release.draft"),
+		Prerelease:             config.GetBool("release.prerelease"),
+		MakeLatest:             makeLatest,
+		TargetCommitish:        config.GetString("release.targetCommitish"),
+		DiscussionCategoryName: config.GetString("release.discussionCategory"),
+	}
+	if text := config.GetString("release.nameTemplate"); text != "" {
+		nameTemplate, err := monoreleaser.NewReleaseNameTemplate(text)
+		if err != nil {
+			return monoreleaser.ProviderOptions{}, err
+		}
+		providerOpts.NameTemplate = nameTemplate
+	}
+
+	return providerOpts, nil
+}
+
+// initHelmRepository creates the chart repository Helm charts are published to, if the helm key is configured.
+func initHelmRepository(config *viper.Viper) *monoreleaser.HelmRepository {
+	if !config.IsSet("helm") {
+		return nil
+	}
+
+	return &monoreleaser.HelmRepository{
+		URL:    config.GetString("helm.url"),
+		Branch: config.GetString("helm.branch"),
+		Dir:    config.GetString("helm.dir"),
+	}
+}
+
+// initRepository creates the Repository for the configured git backend.
+// The default backend is go-git, the "binary" backend uses the git binary found in the PATH.
+func initRepository(
+	name string,
+	repository *git.Repository,
+	config *viper.Viper,
+	token string,
+) (monoreleaser.Repository, error) {
+	var deepenOpts *monoreleaser.DeepenOptions
+	if config.GetBool("git.autoDeepen") {
+		deepenOpts = &monoreleaser.DeepenOptions{
+			Remote:   config.GetString("git.remote"),
+			MaxDepth: config.GetInt("git.maxDepth"),
+		}
+		if token != "" {
+			deepenOpts.Auth = &githttp.BasicAuth{Username: "monoreleaser", Password: token}
+		}
+	}
+
+	tagFormats, err := initTagFormats(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend := config.GetString("git.backend"); backend {
+	case "", "go-git":
+		gitRepository := monoreleaser.NewGoGitRepository(name, repository).WithTagFormats(tagFormats)
+		if deepenOpts != nil {
+			gitRepository = gitRepository.WithAutoDeepen(*deepenOpts)
+		}
+		return gitRepository, nil
+	case "binary":
+		workTree, err := repository.Worktree()
+		if err != nil {
+			return nil, err
+		}
+		gitRepository := monoreleaser.NewGitBinaryRepository(name, workTree.Filesystem.Root()).WithTagFormats(tagFormats)
+		if deepenOpts != nil {
+			gitRepository = gitRepository.WithAutoDeepen(*deepenOpts)
+		}
+		return gitRepository, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backend)
+	}
+}
+
+// moduleTagFormat configures the tag name format of a single Module.
+type moduleTagFormat struct {
+	Module string
+	Format string
+}
+
+// initTagFormats parses the configured tag name templates.
+// Per Module formats are configured as a list, because viper lowercases map keys, which are Module paths here.
+func initTagFormats(config *viper.Viper) (monoreleaser.TagFormats, error) {
+	var formats monoreleaser.TagFormats
+	if format := config.GetString("tags.format"); format != "" {
+		tagFormat, err := monoreleaser.NewTagFormat(format)
+		if err != nil {
+			return monoreleaser.TagFormats{}, err
+		}
+		formats.Default = tagFormat
+	}
+
+	var moduleFormats []moduleTagFormat
+	if err := config.UnmarshalKey("tags.modules", &moduleFormats); err != nil {
+		return monoreleaser.TagFormats{}, err
+	}
+	for _, moduleFormat := range moduleFormats {
+		tagFormat, err := monoreleaser.NewTagFormat(moduleFormat.Format)
+		if err != nil {
+			return monoreleaser.TagFormats{}, fmt.Errorf("%s: %w", monoreleaser.ModuleName(moduleFormat.Module), err)
+		}
+		if formats.Modules == nil {
+			formats.Modules = make(map[string]*monoreleaser.TagFormat)
+		}
+		module := moduleFormat.Module
+		if module == monoreleaser.RootModule {
+			module = ""
+		}
+		formats.Modules[module] = tagFormat
+	}
+
+	return formats, nil
+}
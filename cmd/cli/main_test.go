@@ -16,6 +16,7 @@ import (
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	. "github.com/kharf/monoreleaser/internal"
@@ -26,8 +27,18 @@ import (
 )
 
 func newRepo(empty bool) (*git.Repository, []*Commit) {
-	var commits []*Commit
 	gitRepository, _ := git.Init(memory.NewStorage(), memfs.New())
+	return gitRepository, commitTo(gitRepository, empty)
+}
+
+func newDiskRepo(t *testing.T, empty bool) (*git.Repository, []*Commit) {
+	gitRepository, err := git.PlainInit(t.TempDir(), false)
+	require.NoError(t, err)
+	return gitRepository, commitTo(gitRepository, empty)
+}
+
+func commitTo(gitRepository *git.Repository, empty bool) []*Commit {
+	var commits []*Commit
 	workTree, err := gitRepository.Worktree()
 	if err != nil {
 		log.Panic(err)
@@ -84,7 +95,7 @@ func newRepo(empty bool) (*git.Repository, []*Commit) {
 		return i > j
 	})
 
-	return gitRepository, commits
+	return commits
 }
 
 func createServer(t *testing.T, changelog Changelog, releaser *GithubReleaser) *httptest.Server {
@@ -276,9 +287,17 @@ Usage:
   monoreleaser [command]
 
 Available Commands:
+  backfill    Create the missing provider releases of existing tags
   completion  Generate the autocompletion script for the specified shell
   help        Help about any command
+  hooks       Manage the git hooks of the repository
+  lint        Lint commit messages against Conventional Commits
+  modules     List all Modules with their latest release
+  publish     Publish the draft release of a version, once its assets have been uploaded
   release     Release a piece of Software (Module)
+  releases    List the past releases of all Modules or a single Module
+  show        Show the changelog of a past release
+  status      Show the unreleased changes of all Modules or a single Module
 
 Flags:
   -h, --help   help for monoreleaser
@@ -314,10 +333,24 @@ provider: "github"`
 
 Usage:
   monoreleaser release [MODULE] [VERSION] [flags]
+  monoreleaser release [command]
+
+Available Commands:
+  edit        Regenerate the changelog of existing releases and update them at the provider
 
 Flags:
-      --artifacts strings   artifacts to upload alongside the changelog (if supported by the provider)
-  -h, --help                help for release
+      --all                          release every module with changes since its latest release, deriving the versions from the commits
+      --artifacts strings            artifacts to upload alongside the changelog (if supported by the provider)
+      --discussion-category string   category of the discussion started for the release (if supported by the provider)
+      --draft                        create the release as a draft, which is published later (if supported by the provider)
+      --first-parent                 only follow the first parent of merge commits, listing merged PRs instead of their commits
+  -h, --help                         help for release
+      --make-latest string           whether the release becomes the latest release: true, false or legacy (if supported by the provider)
+      --name-template string         template of the release name, e.g. "{{.Module}} {{.Version}}" (defaults to the tag name)
+      --prerelease                   mark the release as a pre-release, which pre-release versions like v1.0.0-rc.1 always are
+      --target-commitish string      branch or commit the provider creates the tag from, if it does not exist yet
+
+Use "monoreleaser release [command] --help" for more information about a command.
 `
 	assert.Equal(t, expectedOutput, buffer.String())
 }
@@ -347,10 +380,24 @@ provider: "github"`
 	expectedOutput := `Error: requires at least 2 arg(s), only received 0
 Usage:
   monoreleaser release [MODULE] [VERSION] [flags]
+  monoreleaser release [command]
+
+Available Commands:
+  edit        Regenerate the changelog of existing releases and update them at the provider
 
 Flags:
-      --artifacts strings   artifacts to upload alongside the changelog (if supported by the provider)
-  -h, --help                help for release
+      --all                          release every module with changes since its latest release, deriving the versions from the commits
+      --artifacts strings            artifacts to upload alongside the changelog (if supported by the provider)
+      --discussion-category string   category of the discussion started for the release (if supported by the provider)
+      --draft                        create the release as a draft, which is published later (if supported by the provider)
+      --first-parent                 only follow the first parent of merge commits, listing merged PRs instead of their commits
+  -h, --help                         help for release
+      --make-latest string           whether the release becomes the latest release: true, false or legacy (if supported by the provider)
+      --name-template string         template of the release name, e.g. "{{.Module}} {{.Version}}" (defaults to the tag name)
+      --prerelease                   mark the release as a pre-release, which pre-release versions like v1.0.0-rc.1 always are
+      --target-commitish string      branch or commit the provider creates the tag from, if it does not exist yet
+
+Use "monoreleaser release [command] --help" for more information about a command.
 
 `
 	assert.Equal(t, expectedOutput, buffer.String())
@@ -432,4 +479,699 @@ github:
 	assert.NoError(t, err)
 	expectedOutput := ""
 	assert.Equal(t, expectedOutput, buffer.String())
-}
\ No newline at end of file
+}
+func TestReleaseCommand_GitBinaryBackend(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `owner: "kharf"
+name: "monoreleaser"
+provider: "github"
+git:
+  backend: "binary"
+github:
+  token: "abcd"`
+	configBuffer := bytes.NewBufferString(configYaml)
+	err := config.ReadConfig(configBuffer)
+	assert.NoError(t, err)
+
+	repo, diffs := newDiskRepo(t, false)
+	rootCmdBuilder, err := initCli(repo, config, afero.NewMemMapFs())
+	assert.NoError(t, err)
+
+	changes := Extract(diffs)
+	changelog, _ := GenerateChangelog(changes)
+
+	releaser := rootCmdBuilder.releaseCmdBuilder.releaser
+	ghReleaser, ok := releaser.(*GithubReleaser)
+	assert.True(t, ok)
+	ts := createServer(t, changelog, ghReleaser)
+	defer ts.Close()
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", ".", "v1"})
+
+	_, err = rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Equal(t, "", buffer.String())
+
+	tags, err := repo.Tags()
+	require.NoError(t, err)
+	tag, err := tags.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/tags/v1", tag.Name().String())
+}
+
+func TestInitCli_UnknownGitBackend(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+git:
+  backend: "svn"`
+	configBuffer := bytes.NewBufferString(configYaml)
+	err := config.ReadConfig(configBuffer)
+	assert.NoError(t, err)
+
+	repo, _ := newRepo(false)
+	_, err = initCli(repo, config, afero.NewMemMapFs())
+	assert.ErrorIs(t, err, ErrUnknownBackend)
+}
+
+type recordingReleaser struct {
+	releases []string
+	changes  [][]Change
+	err      error
+}
+
+var _ Releaser = &recordingReleaser{}
+
+func (rel *recordingReleaser) Release(version string, opts ReleaseOptions) error {
+	if rel.err != nil {
+		return rel.err
+	}
+	rel.releases = append(rel.releases, tagName(opts.Module, version))
+	rel.changes = append(rel.changes, opts.Changes)
+	return nil
+}
+
+func tagName(module string, version string) string {
+	if module == "" {
+		return version
+	}
+	return module + "/" + version
+}
+
+func newReleaseAllCli(t *testing.T) (*RootCommandBuilder, *recordingReleaser) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `owner: "kharf"
+name: "monoreleaser"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, commits := newRepo(false)
+	_, err = repo.CreateTag("subdir/v1.0.0", plumbing.NewHash(commits[1].Hash), nil)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "go.mod", []byte("module github.com/kharf/monoreleaser\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "subdir/package.json", []byte(`{"name": "subdir"}`), 0o644))
+	// docs has no changes, but depends on the root module
+	require.NoError(t, afero.WriteFile(fs, "docs/go.mod", []byte(`module github.com/kharf/monoreleaser/docs
+
+require github.com/kharf/monoreleaser v0.0.0
+`), 0o644))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	releaser := &recordingReleaser{}
+	rootCmdBuilder.releaseCmdBuilder.releaser = releaser
+	return rootCmdBuilder, releaser
+}
+
+func TestReleaseCommand_All(t *testing.T) {
+	rootCmdBuilder, releaser := newReleaseAllCli(t)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "--all"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0", "docs/v0.0.1", "subdir/v1.1.0"}, releaser.releases)
+	assert.Equal(t, [][]Change{
+		nil,
+		{{Message: "bumped dependency . to v1.0.0", Semantic: Patch}},
+		nil,
+	}, releaser.changes)
+	expectedOutput := `MODULE  PREVIOUS       VERSION  CHANGES
+.       -              v1.0.0   10
+docs    -              v0.0.1   1
+subdir  subdir/v1.0.0  v1.1.0   1
+`
+	assert.Equal(t, expectedOutput, buffer.String())
+}
+
+func TestReleaseCommand_All_ReleaseFailed(t *testing.T) {
+	rootCmdBuilder, releaser := newReleaseAllCli(t)
+	releaser.err = ErrRequestUnsuccessful
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "--all"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrRequestUnsuccessful)
+	assert.Empty(t, releaser.releases)
+}
+
+func TestReleaseCommand_All_NoArgs(t *testing.T) {
+	rootCmdBuilder, releaser := newReleaseAllCli(t)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "--all", "subdir", "v1.1.0"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.Error(t, err)
+	assert.Empty(t, releaser.releases)
+}
+
+func TestReleaseCommand_All_DependencyCycle(t *testing.T) {
+	rootCmdBuilder, releaser := newReleaseAllCli(t)
+	fs := rootCmdBuilder.releaseCmdBuilder.fs
+	require.NoError(t, afero.WriteFile(fs, "go.mod", []byte(`module github.com/kharf/monoreleaser
+
+require github.com/kharf/monoreleaser/docs v0.0.0
+`), 0o644))
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "--all"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	assert.Empty(t, releaser.releases)
+}
+
+func TestReleaseCommand_GoModuleMajorVersion(t *testing.T) {
+	rootCmdBuilder, releaser := newReleaseAllCli(t)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "docs", "v2.0.0"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrUnresolvableGoTag)
+	assert.Empty(t, releaser.releases)
+}
+
+func TestReleaseCommand_GoModuleMajorVersionSubdirectory(t *testing.T) {
+	rootCmdBuilder, _ := newReleaseAllCli(t)
+	fs := rootCmdBuilder.releaseCmdBuilder.fs
+	require.NoError(t, afero.WriteFile(fs, "docs/v2/go.mod", []byte("module github.com/kharf/monoreleaser/docs/v2\n"), 0o644))
+
+	var released ReleaseOptions
+	rootCmdBuilder.releaseCmdBuilder.releaser = releaserFunc(func(_ string, opts ReleaseOptions) error {
+		released = opts
+		return nil
+	})
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "docs/v2", "v2.0.0"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Equal(t, "docs/v2", released.Module)
+	assert.Equal(t, "docs", released.TagModule)
+}
+
+type releaserFunc func(version string, opts ReleaseOptions) error
+
+func (release releaserFunc) Release(version string, opts ReleaseOptions) error {
+	return release(version, opts)
+}
+
+func TestReleaseCommand_TagFormat(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+tags:
+  format: "{{.Module}}@v{{.Version}}"
+  modules:
+    - module: "."
+      format: "v{{.Version}}"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, _ := newRepo(false)
+	rootCmdBuilder, err := initCli(repo, config, afero.NewMemMapFs())
+	require.NoError(t, err)
+
+	repository := rootCmdBuilder.releaseCmdBuilder.repository
+	tag, err := repository.Tag("v1.0.0", TagOptions{Module: "subdir"})
+	require.NoError(t, err)
+	assert.Equal(t, "subdir@v1.0.0", tag.Name)
+
+	tag, err = repository.Tag("1.0.0", TagOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", tag.Name)
+
+	tags, err := repository.GetTags(GetTagOptions{Module: "subdir"})
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "v1.0.0", tags[0].Version)
+}
+
+func TestInitCli_InvalidTagFormat(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+tags:
+  format: "{{.Module}}"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, _ := newRepo(false)
+	_, err = initCli(repo, config, afero.NewMemMapFs())
+	assert.ErrorIs(t, err, ErrInvalidTagFormat)
+}
+
+func TestReleaseCommand_ConfiguredModule(t *testing.T) {
+	rootCmdBuilder, _ := newReleaseAllCli(t)
+	rootCmdBuilder.releaseCmdBuilder.discoverOpts.Modules = []Module{
+		NewConfiguredModule("docs-site", ModulePaths{Include: []string{"docs"}, Exclude: []string{"docs/drafts"}}),
+	}
+
+	var released ReleaseOptions
+	rootCmdBuilder.releaseCmdBuilder.releaser = releaserFunc(func(_ string, opts ReleaseOptions) error {
+		released = opts
+		return nil
+	})
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	// go get could never resolve the tag of the Go module in docs, but it is tagged by its name anyway
+	rootCmd.SetArgs([]string{"release", "docs-site", "v2.0.0"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Equal(t, "docs", released.Module)
+	assert.Equal(t, "docs-site", released.TagModule)
+	assert.Equal(t, ModulePaths{Include: []string{"docs"}, Exclude: []string{"docs/drafts"}}, released.Paths)
+}
+
+func TestReleaseCommand_ConfiguredModuleNamedByDirectory(t *testing.T) {
+	rootCmdBuilder, releaser := newReleaseAllCli(t)
+	rootCmdBuilder.releaseCmdBuilder.discoverOpts.Modules = []Module{
+		NewConfiguredModule("docs", ModulePaths{Include: []string{"docs", "go.mod"}}),
+	}
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "docs", "v2.0.0"})
+
+	_, err := rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrUnresolvableGoTag)
+	assert.Empty(t, releaser.releases)
+}
+
+func TestReleaseCommand_VersionFiles(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+release:
+  commitMessage: "release {{.Module}} {{.Version}}"
+modules:
+  - name: "web"
+    paths:
+      - "subdir"
+    versionFiles:
+      - "package.json"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, _ := newDiskRepo(t, false)
+	gitConfig, err := repo.Config()
+	require.NoError(t, err)
+	gitConfig.User.Name = "orca"
+	gitConfig.User.Email = "orca-dev@mail.com"
+	require.NoError(t, repo.SetConfig(gitConfig))
+
+	workTree, err := repo.Worktree()
+	require.NoError(t, err)
+	fs := afero.NewBasePathFs(afero.NewOsFs(), workTree.Filesystem.Root())
+	require.NoError(t, afero.WriteFile(fs, "subdir/package.json", []byte(`{"name": "web", "version": "0.0.0"}`), 0o644))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	var released ReleaseOptions
+	rootCmdBuilder.releaseCmdBuilder.releaser = releaserFunc(func(_ string, opts ReleaseOptions) error {
+		released = opts
+		return nil
+	})
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "web", "v1.0.0"})
+
+	_, err = rootCmd.ExecuteC()
+	require.NoError(t, err)
+	require.NotNil(t, released.VersionCommit)
+	assert.Equal(t, "release web v1.0.0", released.VersionCommit.Message)
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash().String(), released.VersionCommit.Hash)
+
+	content, err := afero.ReadFile(fs, "subdir/package.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "web", "version": "1.0.0"}`, string(content))
+}
+
+func TestReleaseCommand_HelmChart(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+helm:
+  url: "https://orca.github.io/monoreleaser"
+  branch: "gh-pages"
+modules:
+  - name: "api-chart"
+    paths:
+      - "subdir"
+    chart: true`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, _ := newDiskRepo(t, false)
+	gitConfig, err := repo.Config()
+	require.NoError(t, err)
+	gitConfig.User.Name = "orca"
+	gitConfig.User.Email = "orca-dev@mail.com"
+	require.NoError(t, repo.SetConfig(gitConfig))
+
+	workTree, err := repo.Worktree()
+	require.NoError(t, err)
+	fs := afero.NewBasePathFs(afero.NewOsFs(), workTree.Filesystem.Root())
+	require.NoError(t, afero.WriteFile(fs, "subdir/Chart.yaml", []byte("apiVersion: v2\nname: api\nversion: 0.1.0\n"), 0o644))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	var released ReleaseOptions
+	rootCmdBuilder.releaseCmdBuilder.releaser = releaserFunc(func(_ string, opts ReleaseOptions) error {
+		released = opts
+		return nil
+	})
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "api-chart", "v1.0.0"})
+
+	_, err = rootCmd.ExecuteC()
+	require.NoError(t, err)
+	require.NotNil(t, released.VersionCommit)
+	require.Len(t, released.Artifacts, 1)
+	assert.Equal(t, "api-1.0.0.tgz", released.Artifacts[0].Name)
+
+	content, err := afero.ReadFile(fs, "subdir/Chart.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: v2\nname: api\nversion: 1.0.0\n", string(content))
+
+	gitRepository := NewGoGitRepository("monoreleaser", repo)
+	index, err := gitRepository.ReadFile("gh-pages", "index.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "https://orca.github.io/monoreleaser/api-1.0.0.tgz")
+	_, err = gitRepository.ReadFile("gh-pages", "api-1.0.0.tgz")
+	assert.NoError(t, err)
+}
+
+func TestReleaseCommand_VersionSource(t *testing.T) {
+	testCases := []struct {
+		name            string
+		versionSource   string
+		args            []string
+		versionFile     string
+		expectedVersion string
+	}{
+		{
+			name:            "File",
+			versionSource:   "file",
+			args:            []string{"release", "web"},
+			versionFile:     "1.2.0\n",
+			expectedVersion: "v1.2.0",
+		},
+		{
+			name:            "Commits",
+			versionSource:   "commits",
+			args:            []string{"release", "web"},
+			expectedVersion: "v0.1.0",
+		},
+		{
+			name:            "ExplicitVersion",
+			versionSource:   "commits",
+			args:            []string{"release", "web", "v2.0.0"},
+			expectedVersion: "v2.0.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := viper.New()
+			config.SetConfigType("yaml")
+			configYaml := `name: "monoreleaser"
+modules:
+  - name: "web"
+    paths:
+      - "subdir"
+    versionSource: "` + tc.versionSource + `"`
+			fs := afero.NewMemMapFs()
+			if tc.versionFile != "" {
+				configYaml += `
+    versionFiles:
+      - "VERSION"`
+				require.NoError(t, afero.WriteFile(fs, "subdir/VERSION", []byte(tc.versionFile), 0o644))
+			}
+			err := config.ReadConfig(bytes.NewBufferString(configYaml))
+			require.NoError(t, err)
+
+			repo, _ := newRepo(false)
+			rootCmdBuilder, err := initCli(repo, config, fs)
+			require.NoError(t, err)
+
+			var releasedVersion string
+			rootCmdBuilder.releaseCmdBuilder.releaser = releaserFunc(func(version string, _ ReleaseOptions) error {
+				releasedVersion = version
+				return nil
+			})
+
+			rootCmd := rootCmdBuilder.Build()
+			buffer := &bytes.Buffer{}
+			rootCmd.SetOut(buffer)
+			rootCmd.SetErr(buffer)
+			rootCmd.SetArgs(tc.args)
+
+			_, err = rootCmd.ExecuteC()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, releasedVersion)
+		})
+	}
+}
+
+func TestReleaseCommand_VersionSource_Unchanged(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+modules:
+  - name: "web"
+    paths:
+      - "subdir"
+    versionFiles:
+      - "VERSION"
+    versionSource: "file"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "subdir/VERSION", []byte("1.0.0\n"), 0o644))
+
+	repo, commits := newRepo(false)
+	_, err = repo.CreateTag("web/v1.0.0", plumbing.NewHash(commits[len(commits)-1].Hash), nil)
+	require.NoError(t, err)
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+	rootCmdBuilder.releaseCmdBuilder.releaser = releaserFunc(func(string, ReleaseOptions) error {
+		t.Fatal("nothing should be released")
+		return nil
+	})
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"release", "web"})
+
+	_, err = rootCmd.ExecuteC()
+	require.NoError(t, err)
+	assert.Equal(t, "web has nothing to release\n", buffer.String())
+
+	// a lowered version is refused
+	require.NoError(t, afero.WriteFile(fs, "subdir/VERSION", []byte("0.9.0\n"), 0o644))
+	rootCmd.SetArgs([]string{"release", "web"})
+	_, err = rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrVersionNotGreater)
+}
+
+func TestInitCli_InvalidVersionSource(t *testing.T) {
+	for configYaml, expected := range map[string]error{
+		`modules: [{name: "web", paths: ["subdir"], versionSource: "branch"}]`: ErrInvalidVersionSource,
+		`modules: [{name: "web", paths: ["subdir"], versionSource: "file"}]`:   ErrInvalidModuleConfig,
+	} {
+		config := viper.New()
+		config.SetConfigType("yaml")
+		require.NoError(t, config.ReadConfig(bytes.NewBufferString(configYaml)))
+
+		repo, _ := newRepo(false)
+		_, err := initCli(repo, config, afero.NewMemMapFs())
+		assert.ErrorIs(t, err, ErrInvalidModuleConfig)
+		assert.ErrorIs(t, err, expected)
+	}
+}
+
+func TestReleaseCommand_VersionPolicy(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+release:
+  versionPolicy: "strict"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, commits := newRepo(false)
+	_, err = repo.CreateTag("v1.0.0", plumbing.NewHash(commits[len(commits)-1].Hash), nil)
+	require.NoError(t, err)
+
+	rootCmdBuilder, err := initCli(repo, config, afero.NewMemMapFs())
+	require.NoError(t, err)
+
+	var releasedVersion string
+	rootCmdBuilder.releaseCmdBuilder.releaser = releaserFunc(func(version string, _ ReleaseOptions) error {
+		releasedVersion = version
+		return nil
+	})
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+
+	// the commits since v1.0.0 contain a breaking change, which justifies v2.0.0 at most
+	rootCmd.SetArgs([]string{"release", ".", "v3.0.0"})
+	_, err = rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrVersionSkipped)
+	assert.Empty(t, releasedVersion)
+
+	rootCmd.SetArgs([]string{"release", ".", "v1.0.0"})
+	_, err = rootCmd.ExecuteC()
+	assert.ErrorIs(t, err, ErrVersionExists)
+
+	rootCmd.SetArgs([]string{"release", ".", "v2.0.0"})
+	_, err = rootCmd.ExecuteC()
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0", releasedVersion)
+}
+
+func TestInitCli_InvalidVersionPolicy(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	err := config.ReadConfig(bytes.NewBufferString(`release: {versionPolicy: "yolo"}`))
+	require.NoError(t, err)
+
+	repo, _ := newRepo(false)
+	_, err = initCli(repo, config, afero.NewMemMapFs())
+	assert.ErrorIs(t, err, ErrInvalidVersionPolicy)
+}
+
+func TestReleaseCommand_ProviderOptions(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var release map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&release))
+		assert.Equal(t, "subdir/v2.0.0-rc.1", release["tag_name"])
+		assert.Equal(t, "subdir 2.0.0-rc.1", release["name"])
+		assert.Equal(t, true, release["draft"])
+		assert.Equal(t, true, release["prerelease"])
+		assert.Equal(t, "false", release["make_latest"])
+		assert.NotContains(t, release, "target_commitish")
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer ts.Close()
+
+	_, err := runGithubCli(
+		t, repo, fs, ts.URL,
+		"release", "subdir", "v2.0.0-rc.1",
+		"--draft", "--make-latest", "false", "--name-template", "{{.Module}} {{.Version}}",
+	)
+	assert.NoError(t, err)
+}
+
+func TestReleaseCommand_InvalidProviderOptions(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+
+	_, err := runCli(t, repo, fs, "release", "subdir", "v2.0.0", "--make-latest", "always")
+	assert.ErrorIs(t, err, ErrInvalidMakeLatest)
+
+	_, err = runCli(t, repo, fs, "release", "subdir", "v2.0.0", "--name-template", "{{.Unknown}}")
+	assert.ErrorIs(t, err, ErrInvalidReleaseName)
+
+	_, err = repo.Tag("subdir/v2.0.0")
+	assert.Error(t, err)
+}
+
+func TestInitProviderOptions(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `release:
+  draft: true
+  makeLatest: legacy
+  targetCommitish: main
+  discussionCategory: Announcements
+  nameTemplate: "{{.Tag}}"`
+	require.NoError(t, config.ReadConfig(bytes.NewBufferString(configYaml)))
+
+	providerOpts, err := initProviderOptions(config)
+	require.NoError(t, err)
+	assert.True(t, providerOpts.Draft)
+	assert.False(t, providerOpts.Prerelease)
+	assert.Equal(t, MakeLatestLegacy, providerOpts.MakeLatest)
+	assert.Equal(t, "main", providerOpts.TargetCommitish)
+	assert.Equal(t, "Announcements", providerOpts.DiscussionCategoryName)
+	name, err := providerOpts.NameTemplate.Name(Tag{Name: "v1.0.0", Version: "v1.0.0"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", name)
+
+	config.Set("release.makeLatest", "always")
+	_, err = initProviderOptions(config)
+	assert.ErrorIs(t, err, ErrInvalidMakeLatest)
+}
+
+func TestInitRetryPolicy(t *testing.T) {
+	config := viper.New()
+	assert.Equal(t, DefaultRetryPolicy, initRetryPolicy(config))
+
+	config.SetConfigType("yaml")
+	require.NoError(t, config.ReadConfig(bytes.NewBufferString("http:\n  maxRetries: 0\n  maxWait: 10m")))
+	policy := initRetryPolicy(config)
+	assert.Equal(t, 0, policy.MaxRetries)
+	assert.Equal(t, 10*time.Minute, policy.MaxWait)
+	assert.Equal(t, DefaultRetryPolicy.BaseDelay, policy.BaseDelay)
+}
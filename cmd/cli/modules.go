@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	monoreleaser "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var ErrInvalidModuleConfig = errors.New("invalid module configuration")
+
+type ModulesCommandBuilder struct {
+	repository   monoreleaser.Repository
+	fs           afero.Fs
+	discoverOpts monoreleaser.DiscoverOptions
+}
+
+func (builder ModulesCommandBuilder) Build() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "modules",
+		Short: "List all Modules with their latest release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+			if err != nil {
+				return err
+			}
+
+			writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "MODULE\tLATEST TAG\tUNRELEASED")
+			for _, module := range modules {
+				latestTag, commits, err := monoreleaser.Unreleased(builder.repository, module, monoreleaser.DiffOptions{})
+				if err != nil {
+					return fmt.Errorf("%s: %w", moduleLabel(module.Name, module.Path), err)
+				}
+
+				latestTagName := "-"
+				if latestTag != nil {
+					latestTagName = latestTag.Name
+				}
+				fmt.Fprintf(writer, "%s\t%s\t%d\n", moduleLabel(module.Name, module.Path), latestTagName, len(commits))
+			}
+
+			return writer.Flush()
+		},
+	}
+
+	return cmd
+}
+
+// taggedByName reports whether a configured Module is tagged by a name differing from its directory.
+func taggedByName(name string, module string) bool {
+	return name != "" && name != module
+}
+
+// moduleLabel returns how a Module is passed on the command line, which is its name, if it is configured with one.
+func moduleLabel(name string, module string) string {
+	if name != "" {
+		return name
+	}
+	return monoreleaser.ModuleName(module)
+}
+
+// moduleConfig configures a Module with a logical name, which is independent of its directories.
+// Include adds glob patterns of shared files to the directories, e.g. "proto/**".
+// VersionFiles are written with the released version, which is committed before it is tagged.
+// Chart marks a Helm chart, which is packaged and published on release.
+// VersionSource is either "tags", "file" or "commits", where "file" reads the version from the first of the VersionFiles.
+type moduleConfig struct {
+	Name          string
+	Paths         []string
+	Include       []string
+	Exclude       []string
+	VersionFiles  []string
+	Chart         bool
+	VersionSource string
+	Assets        []string
+}
+
+// initModules creates the configured Modules.
+func initModules(config *viper.Viper) ([]monoreleaser.Module, error) {
+	var moduleConfigs []moduleConfig
+	if err := config.UnmarshalKey("modules", &moduleConfigs); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(moduleConfigs))
+	modules := make([]monoreleaser.Module, 0, len(moduleConfigs))
+	for _, moduleConfig := range moduleConfigs {
+		if moduleConfig.Name == "" || len(moduleConfig.Paths) == 0 {
+			return nil, fmt.Errorf("%w: modules need a name and at least one path", ErrInvalidModuleConfig)
+		}
+		if names[moduleConfig.Name] {
+			return nil, fmt.Errorf("%w: %s is configured more than once", ErrInvalidModuleConfig, moduleConfig.Name)
+		}
+		names[moduleConfig.Name] = true
+
+		module := monoreleaser.NewConfiguredModule(moduleConfig.Name, monoreleaser.ModulePaths{
+			Include: append(moduleConfig.Paths, moduleConfig.Include...),
+			Exclude: moduleConfig.Exclude,
+		})
+		module.VersionFiles = moduleConfig.VersionFiles
+		module.Chart = moduleConfig.Chart
+		module.Assets = moduleConfig.Assets
+
+		versionSource, err := monoreleaser.ParseVersionSource(moduleConfig.VersionSource)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidModuleConfig, moduleConfig.Name, err)
+		}
+		module.VersionSource = versionSource
+		if module.VersionSource == monoreleaser.VersionSourceFile && len(module.VersionFiles) == 0 {
+			return nil, fmt.Errorf("%w: %s reads its version from a file, but has no versionFiles", ErrInvalidModuleConfig, moduleConfig.Name)
+		}
+		modules = append(modules, module)
+	}
+
+	return modules, nil
+}
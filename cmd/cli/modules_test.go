@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModulesCommand(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, commits := newRepo(false)
+	_, err = repo.CreateTag("subdir/v1.0.0", plumbing.NewHash(commits[1].Hash), nil)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "go.mod", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "subdir/package.json", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "docs/README.md", []byte{}, 0o644))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"modules"})
+
+	_, err = rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	expectedOutput := `MODULE  LATEST TAG     UNRELEASED
+.       -              10
+subdir  subdir/v1.0.0  1
+`
+	assert.Equal(t, expectedOutput, buffer.String())
+}
+
+func TestModulesCommand_Globs(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+discovery:
+  globs:
+    - "sub*"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, _ := newRepo(false)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "go.mod", []byte{}, 0o644))
+	require.NoError(t, fs.MkdirAll("subdir", 0o755))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"modules"})
+
+	_, err = rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	expectedOutput := `MODULE  LATEST TAG  UNRELEASED
+subdir  -           2
+`
+	assert.Equal(t, expectedOutput, buffer.String())
+}
+
+func TestModulesCommand_Configured(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+modules:
+  - name: "lib"
+    paths:
+      - "subdir"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, commits := newRepo(false)
+	_, err = repo.CreateTag("lib/v1.0.0", plumbing.NewHash(commits[1].Hash), nil)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "go.mod", []byte{}, 0o644))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"modules"})
+
+	_, err = rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	expectedOutput := `MODULE  LATEST TAG  UNRELEASED
+.       -           10
+lib     lib/v1.0.0  1
+`
+	assert.Equal(t, expectedOutput, buffer.String())
+}
+
+func TestInitCli_InvalidModuleConfig(t *testing.T) {
+	for _, configYaml := range []string{
+		`modules: [{name: "lib"}]`,
+		`modules: [{paths: ["subdir"]}]`,
+		`modules: [{name: "lib", paths: ["subdir"]}, {name: "lib", paths: ["docs"]}]`,
+	} {
+		config := viper.New()
+		config.SetConfigType("yaml")
+		err := config.ReadConfig(bytes.NewBufferString(configYaml))
+		require.NoError(t, err)
+
+		repo, _ := newRepo(false)
+		_, err = initCli(repo, config, afero.NewMemMapFs())
+		assert.ErrorIs(t, err, ErrInvalidModuleConfig, configYaml)
+	}
+}
+
+func TestModulesCommand_ConfiguredSharedPaths(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	configYaml := `name: "monoreleaser"
+discovery:
+  globs:
+    - "sub*"
+modules:
+  - name: "subdir"
+    paths:
+      - "subdir"
+    include:
+      - "1*"
+    exclude:
+      - "**/0"`
+	err := config.ReadConfig(bytes.NewBufferString(configYaml))
+	require.NoError(t, err)
+
+	repo, _ := newRepo(false)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("subdir", 0o755))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(buffer)
+	rootCmd.SetArgs([]string{"modules"})
+
+	_, err = rootCmd.ExecuteC()
+	assert.NoError(t, err)
+	expectedOutput := `MODULE  LATEST TAG  UNRELEASED
+subdir  -           2
+`
+	assert.Equal(t, expectedOutput, buffer.String())
+}
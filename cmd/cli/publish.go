@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	monoreleaser "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var ErrPublishUnsupported = errors.New("the provider can not publish draft releases")
+
+type PublishCommandBuilder struct {
+	releaser     monoreleaser.Releaser
+	repository   monoreleaser.Repository
+	fs           afero.Fs
+	discoverOpts monoreleaser.DiscoverOptions
+	// assets are the patterns of the files, which the drafts of every Module need to be published.
+	assets []string
+	// makeLatest is the configured release.makeLatest, which the flag overrides.
+	makeLatest monoreleaser.MakeLatest
+}
+
+func (builder PublishCommandBuilder) Build() *cobra.Command {
+	var makeLatest *string
+	cmd := &cobra.Command{
+		Use:   "publish MODULE VERSION",
+		Short: "Publish the draft release of a version, once its assets have been uploaded",
+		Long: `Publish the draft release of a version, once its assets have been uploaded.
+The draft is not published, while files matching publish.assets or the assets of a configured module are missing.`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			publisher, ok := builder.releaser.(monoreleaser.ReleasePublisher)
+			if !ok {
+				return ErrPublishUnsupported
+			}
+
+			opts := monoreleaser.PublishOptions{Assets: builder.assets, MakeLatest: builder.makeLatest}
+			if cmd.Flags().Changed("make-latest") {
+				mrMakeLatest, err := monoreleaser.ParseMakeLatest(*makeLatest)
+				if err != nil {
+					return err
+				}
+				opts.MakeLatest = mrMakeLatest
+			}
+
+			modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+			if err != nil {
+				return err
+			}
+			module := resolveModule(modules, args[0])
+
+			tag, err := monoreleaser.Publish(builder.repository, publisher, module, args[1], opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s published\n", tag.Name)
+			return nil
+		},
+	}
+
+	makeLatest = cmd.Flags().
+		String("make-latest", "", "whether the release becomes the latest release: true, false or legacy")
+	return cmd
+}
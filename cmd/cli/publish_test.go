@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishCommand(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+	var patched map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`[{"id": 1, "tag_name": "v1.0.0", "draft": false}, {"id": 2, "tag_name": "subdir/v1.0.0", "draft": true}]`))
+		case http.MethodPatch:
+			assert.Equal(t, "/repos/kharf/monoreleaser/releases/2", r.URL.Path)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&patched))
+			w.Write([]byte(`{"id": 2}`))
+		}
+	}))
+	defer ts.Close()
+
+	out, err := runGithubCli(t, repo, fs, ts.URL, "publish", "subdir", "1.0.0", "--make-latest", "true")
+	require.NoError(t, err)
+	assert.Equal(t, "subdir/v1.0.0 published\n", out)
+	assert.Equal(t, map[string]any{"draft": false, "make_latest": "true"}, patched)
+
+	_, err = runGithubCli(t, repo, fs, ts.URL, "publish", ".", "v1.0.0")
+	assert.ErrorIs(t, err, ErrReleasePublished)
+
+	_, err = runGithubCli(t, repo, fs, ts.URL, "publish", ".", "v0.1.0")
+	assert.ErrorIs(t, err, ErrDraftNotFound)
+
+	_, err = runGithubCli(t, repo, fs, ts.URL, "publish", "subdir", "1.0.0", "--make-latest", "always")
+	assert.ErrorIs(t, err, ErrInvalidMakeLatest)
+}
+
+func TestPublishCommand_Unsupported(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+
+	_, err := runCli(t, repo, fs, "publish", "subdir", "1.0.0")
+	assert.ErrorIs(t, err, ErrPublishUnsupported)
+}
+
+func TestInitModules_Assets(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	require.NoError(t, config.ReadConfig(bytes.NewBufferString(`modules: [{name: "web", paths: ["subdir"], assets: ["web-*.tar.gz"]}]`)))
+
+	modules, err := initModules(config)
+	require.NoError(t, err)
+	require.Len(t, modules, 1)
+	assert.Equal(t, []string{"web-*.tar.gz"}, modules[0].Assets)
+}
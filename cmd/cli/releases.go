@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	monoreleaser "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type ReleasesCommandBuilder struct {
+	repository   monoreleaser.Repository
+	fs           afero.Fs
+	discoverOpts monoreleaser.DiscoverOptions
+	// versionCommitMessage is the configured release.commitMessage, whose Commits are left out of the releases.
+	versionCommitMessage string
+}
+
+func (builder ReleasesCommandBuilder) Build() *cobra.Command {
+	var firstParent *bool
+	cmd := &cobra.Command{
+		Use:   "releases [MODULE]",
+		Short: "List the past releases of all Modules or a single Module",
+		Long:  "List the tag, the date and the number of commits since the previous release of each past release.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+			if err != nil {
+				return err
+			}
+			if len(args) != 0 {
+				modules = []monoreleaser.Module{resolveModule(modules, args[0])}
+			}
+
+			writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "MODULE\tTAG\tDATE\tCOMMITS")
+			for _, module := range modules {
+				releases, err := monoreleaser.ListReleases(
+					builder.repository,
+					module,
+					monoreleaser.PastReleaseOptions{FirstParent: *firstParent, VersionCommitMessage: builder.versionCommitMessage},
+				)
+				if err != nil {
+					return fmt.Errorf("%s: %w", moduleLabel(module.Name, module.Path), err)
+				}
+
+				for _, release := range releases {
+					fmt.Fprintf(
+						writer,
+						"%s\t%s\t%s\t%d\n",
+						moduleLabel(module.Name, module.Path),
+						release.Tag.Name,
+						release.Date.Format("2006-01-02"),
+						len(release.Commits),
+					)
+				}
+			}
+
+			return writer.Flush()
+		},
+	}
+
+	firstParent = cmd.Flags().
+		Bool("first-parent", false, "only follow the first parent of merge commits, counting merged PRs instead of their commits")
+	return cmd
+}
+
+type ShowCommandBuilder struct {
+	repository   monoreleaser.Repository
+	fs           afero.Fs
+	discoverOpts monoreleaser.DiscoverOptions
+	// versionCommitMessage is the configured release.commitMessage, whose Commits are left out of the releases.
+	versionCommitMessage string
+}
+
+func (builder ShowCommandBuilder) Build() *cobra.Command {
+	var firstParent *bool
+	cmd := &cobra.Command{
+		Use:   "show MODULE VERSION",
+		Short: "Show the changelog of a past release",
+		Long:  "Show the changelog of a past release, which is regenerated from the commits since the previous release.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+			if err != nil {
+				return err
+			}
+			module := resolveModule(modules, args[0])
+
+			_, changelog, err := monoreleaser.ShowRelease(
+				builder.repository,
+				module,
+				args[1],
+				monoreleaser.PastReleaseOptions{FirstParent: *firstParent, VersionCommitMessage: builder.versionCommitMessage},
+			)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), changelog)
+			return nil
+		},
+	}
+
+	firstParent = cmd.Flags().
+		Bool("first-parent", false, "only follow the first parent of merge commits, listing merged PRs instead of their commits")
+	return cmd
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReleasesCli(t *testing.T) (*git.Repository, []*Commit, afero.Fs) {
+	repo, commits := newRepo(false)
+	for tag, commit := range map[string]*Commit{"v0.1.0": commits[9], "v1.0.0": commits[5], "subdir/v1.0.0": commits[1]} {
+		_, err := repo.CreateTag(tag, plumbing.NewHash(commit.Hash), nil)
+		require.NoError(t, err)
+	}
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "go.mod", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "subdir/package.json", []byte{}, 0o644))
+	return repo, commits, fs
+}
+
+func runCli(t *testing.T, repo *git.Repository, fs afero.Fs, args ...string) (string, error) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	require.NoError(t, config.ReadConfig(bytes.NewBufferString(`name: "monoreleaser"`)))
+
+	rootCmdBuilder, err := initCli(repo, config, fs)
+	require.NoError(t, err)
+
+	rootCmd := rootCmdBuilder.Build()
+	buffer := &bytes.Buffer{}
+	rootCmd.SetOut(buffer)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(args)
+
+	_, err = rootCmd.ExecuteC()
+	return buffer.String(), err
+}
+
+func TestReleasesCommand(t *testing.T) {
+	repo, _, fs := newReleasesCli(t)
+	today := time.Now().Format("2006-01-02")
+
+	out, err := runCli(t, repo, fs, "releases")
+	require.NoError(t, err)
+	expectedOutput := `MODULE  TAG            DATE        COMMITS
+.       v1.0.0         ` + today + `  4
+.       v0.1.0         ` + today + `  1
+subdir  subdir/v1.0.0  ` + today + `  1
+`
+	assert.Equal(t, expectedOutput, out)
+
+	out, err = runCli(t, repo, fs, "releases", "subdir")
+	require.NoError(t, err)
+	expectedOutput = `MODULE  TAG            DATE        COMMITS
+subdir  subdir/v1.0.0  ` + today + `  1
+`
+	assert.Equal(t, expectedOutput, out)
+}
+
+func TestShowCommand(t *testing.T) {
+	repo, commits, fs := newReleasesCli(t)
+
+	out, err := runCli(t, repo, fs, "show", ".", "v1.0.0")
+	require.NoError(t, err)
+	expected, err := GenerateChangelog(Extract(commits[5:9]))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected)+"\n", out)
+
+	_, err = runCli(t, repo, fs, "show", "subdir", "v2.0.0")
+	assert.ErrorIs(t, err, ErrTagNotFound)
+
+	_, err = runCli(t, repo, fs, "show", "subdir")
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	monoreleaser "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type StatusCommandBuilder struct {
+	repository   monoreleaser.Repository
+	fs           afero.Fs
+	discoverOpts monoreleaser.DiscoverOptions
+}
+
+func (builder StatusCommandBuilder) Build() *cobra.Command {
+	var format *string
+	var firstParent *bool
+	cmd := &cobra.Command{
+		Use:   "status [MODULE]",
+		Short: "Show the unreleased changes of all Modules or a single Module",
+		Long: `Show the latest tag, the next version and the commits since of all Modules, counted per semantic.
+For a single Module, the commits are listed grouped by semantic. Nothing is changed.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			modules, err := monoreleaser.DiscoverModules(builder.fs, builder.discoverOpts)
+			if err != nil {
+				return err
+			}
+			if len(args) != 0 {
+				modules = []monoreleaser.Module{resolveModule(modules, args[0])}
+			}
+
+			statuses := make([]monoreleaser.ModuleStatus, 0, len(modules))
+			for _, module := range modules {
+				status, err := builder.status(module, *firstParent)
+				if err != nil {
+					return err
+				}
+				statuses = append(statuses, *status)
+			}
+
+			return writeStatuses(cmd.OutOrStdout(), *format, statuses, len(args) != 0)
+		},
+	}
+
+	format = cmd.Flags().String("format", "table", "output format: table or json")
+	firstParent = cmd.Flags().
+		Bool("first-parent", false, "only follow the first parent of merge commits, listing merged PRs instead of their commits")
+	return cmd
+}
+
+// status returns the ModuleStatus of a Module, whose next version is read from its version file, if it is the VersionSource.
+func (builder StatusCommandBuilder) status(module monoreleaser.Module, firstParent bool) (*monoreleaser.ModuleStatus, error) {
+	status, err := monoreleaser.GetStatus(builder.repository, module, monoreleaser.DiffOptions{FirstParent: firstParent})
+	if err != nil {
+		return nil, err
+	}
+	if module.VersionSource != monoreleaser.VersionSourceFile {
+		return status, nil
+	}
+
+	version, err := monoreleaser.ReadVersionFile(builder.fs, builder.discoverOpts.Root, module)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", moduleLabel(module.Name, module.Path), err)
+	}
+	status.NextVersion = version
+	// the version has not been raised since the latest release
+	if status.LatestTag != nil && monoreleaser.SameVersion(status.LatestTag.Version, version) {
+		status.NextVersion = ""
+	}
+	return status, nil
+}
+
+// moduleStatusOutput is the JSON representation of a ModuleStatus.
+type moduleStatusOutput struct {
+	Module      string                                   `json:"module"`
+	LatestTag   string                                   `json:"latestTag,omitempty"`
+	NextVersion string                                   `json:"nextVersion,omitempty"`
+	Changes     map[monoreleaser.Semantic][]changeOutput `json:"changes"`
+}
+
+type changeOutput struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+}
+
+// writeStatuses writes the ModuleStatuses in the given format.
+// The table lists the Changes of every Module grouped by their Semantic, if detailed is set.
+func writeStatuses(out io.Writer, format string, statuses []monoreleaser.ModuleStatus, detailed bool) error {
+	switch format {
+	case "table":
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "MODULE\tLATEST TAG\tNEXT VERSION\tMAJOR\tMINOR\tPATCH\tOTHER")
+		for _, status := range statuses {
+			groups := status.ChangesBySemantic()
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%d\t%d\t%d\t%d\n",
+				moduleLabel(status.Name, status.Module),
+				orDash(latestTagName(status.LatestTag)),
+				orDash(status.NextVersion),
+				len(groups[monoreleaser.Major]),
+				len(groups[monoreleaser.Minor]),
+				len(groups[monoreleaser.Patch]),
+				len(groups[monoreleaser.Revert])+len(groups[monoreleaser.Unknown]),
+			)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		if !detailed {
+			return nil
+		}
+		for _, status := range statuses {
+			groups := status.ChangesBySemantic()
+			for _, semantic := range monoreleaser.Semantics {
+				if len(groups[semantic]) == 0 {
+					continue
+				}
+				fmt.Fprintf(out, "\n%s:\n", semantic)
+				for _, change := range groups[semantic] {
+					fmt.Fprintf(out, "  %s %s\n", shortHash(change.Hash), subject(change.Message))
+				}
+			}
+		}
+		return nil
+	case "json":
+		outputs := make([]moduleStatusOutput, 0, len(statuses))
+		for _, status := range statuses {
+			output := moduleStatusOutput{
+				Module:      moduleLabel(status.Name, status.Module),
+				LatestTag:   latestTagName(status.LatestTag),
+				NextVersion: status.NextVersion,
+				Changes:     make(map[monoreleaser.Semantic][]changeOutput),
+			}
+			for semantic, changes := range status.ChangesBySemantic() {
+				for _, change := range changes {
+					output.Changes[semantic] = append(output.Changes[semantic], changeOutput{Hash: change.Hash, Subject: subject(change.Message)})
+				}
+			}
+			outputs = append(outputs, output)
+		}
+
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(outputs)
+	default:
+		return fmt.Errorf("%w: %s (expected table or json)", ErrUnknownOutputFormat, format)
+	}
+}
+
+func latestTagName(tag *monoreleaser.Tag) string {
+	if tag == nil {
+		return ""
+	}
+	return tag.Name
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// shortHash abbreviates the hash of a Commit like git log --oneline does.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// subject returns the first line of a commit message.
+func subject(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(subject)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/kharf/monoreleaser/internal"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatusRepo(t *testing.T) (*git.Repository, []*Commit, afero.Fs) {
+	repo, commits := newRepo(false)
+	_, err := repo.CreateTag("subdir/v1.0.0", plumbing.NewHash(commits[1].Hash), nil)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "go.mod", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "subdir/package.json", []byte{}, 0o644))
+	return repo, commits, fs
+}
+
+func runStatus(t *testing.T, repo *git.Repository, fs afero.Fs, args ...string) (string, error) {
+	return runCli(t, repo, fs, append([]string{"status"}, args...)...)
+}
+
+func TestStatusCommand(t *testing.T) {
+	repo, _, fs := newStatusRepo(t)
+
+	out, err := runStatus(t, repo, fs)
+	require.NoError(t, err)
+	expectedOutput := `MODULE  LATEST TAG     NEXT VERSION  MAJOR  MINOR  PATCH  OTHER
+.       -              v1.0.0        1      8      1      0
+subdir  subdir/v1.0.0  v1.1.0        0      1      0      0
+`
+	assert.Equal(t, expectedOutput, out)
+}
+
+func TestStatusCommand_Module(t *testing.T) {
+	repo, commits, fs := newStatusRepo(t)
+
+	out, err := runStatus(t, repo, fs, "subdir")
+	require.NoError(t, err)
+	expectedOutput := `MODULE  LATEST TAG     NEXT VERSION  MAJOR  MINOR  PATCH  OTHER
+subdir  subdir/v1.0.0  v1.1.0        0      1      0      0
+
+minor:
+  ` + commits[0].Hash[:7] + ` docs: newest
+`
+	assert.Equal(t, expectedOutput, out)
+}
+
+func TestStatusCommand_JSON(t *testing.T) {
+	repo, commits, fs := newStatusRepo(t)
+
+	out, err := runStatus(t, repo, fs, "--format", "json", "subdir")
+	require.NoError(t, err)
+
+	var statuses []moduleStatusOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &statuses))
+	assert.Equal(t, []moduleStatusOutput{{
+		Module:      "subdir",
+		LatestTag:   "subdir/v1.0.0",
+		NextVersion: "v1.1.0",
+		Changes: map[Semantic][]changeOutput{
+			Minor: {{Hash: commits[0].Hash, Subject: "docs: newest"}},
+		},
+	}}, statuses)
+
+	_, err = runStatus(t, repo, fs, "--format", "yaml")
+	assert.ErrorIs(t, err, ErrUnknownOutputFormat)
+}
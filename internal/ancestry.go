@@ -0,0 +1,343 @@
+package monoreleaser
+
+import (
+	"container/heap"
+	"errors"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	commitgraphfmt "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/object/commitgraph"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// ancestryFlag marks from which side of a range a Commit is reachable.
+type ancestryFlag uint8
+
+const (
+	reachableFromNew ancestryFlag = 1 << iota
+	reachableFromOld
+)
+
+// slop is the number of additional uninteresting Commits walked after the range is settled,
+// which compensates for small clock skews between parents and children, like git does.
+const slop = 5
+
+// commitNodeIndex returns an index over the repository's Commits.
+// It makes use of git's commit-graph files if available and falls back to the object storage otherwise.
+// The returned function releases resources held by the index.
+func (repo GoGitRepository) commitNodeIndex() (commitgraph.CommitNodeIndex, func()) {
+	if storage, ok := repo.repository.Storer.(*filesystem.Storage); ok {
+		index, err := commitgraphfmt.OpenChainOrFileIndex(storage.Filesystem())
+		if err == nil {
+			return commitgraph.NewGraphCommitNodeIndex(index, repo.repository.Storer), func() { index.Close() }
+		}
+	}
+
+	return commitgraph.NewObjectCommitNodeIndex(repo.repository.Storer), func() {}
+}
+
+// commitRange determines the Commits reachable from newHash, but not from oldHash, without walking the whole history.
+// Both sides are walked simultaneously from newest to oldest Commit and the walk stops as soon as only Commits reachable from oldHash are left.
+// If oldHash is the ZeroHash, the whole history reachable from newHash is returned.
+// In shallow repositories a ShallowHistoryError is returned, if the range is cut off by the shallow boundary.
+func commitRange(
+	index commitgraph.CommitNodeIndex,
+	newHash plumbing.Hash,
+	oldHash plumbing.Hash,
+	shallow bool,
+) (map[plumbing.Hash]commitgraph.CommitNode, error) {
+	flags := make(map[plumbing.Hash]ancestryFlag)
+	depths := make(map[plumbing.Hash]int)
+	queue := &commitQueue{flags: flags}
+
+	push := func(node commitgraph.CommitNode, flag ancestryFlag) {
+		current := flags[node.ID()]
+		if current|flag == current {
+			return
+		}
+		flags[node.ID()] = current | flag
+		heap.Push(queue, queuedCommit{node: node, flag: current | flag})
+	}
+
+	newNode, err := index.Get(newHash)
+	if err != nil {
+		return nil, err
+	}
+	push(newNode, reachableFromNew)
+	depths[newHash] = 1
+
+	var since *time.Time
+	// the older Commit itself might not have been fetched yet
+	oldMissing := false
+	if oldHash != plumbing.ZeroHash {
+		oldNode, err := index.Get(oldHash)
+		switch {
+		case shallow && errors.Is(err, plumbing.ErrObjectNotFound):
+			oldMissing = true
+		case err != nil:
+			return nil, err
+		default:
+			push(oldNode, reachableFromOld)
+			commitTime := oldNode.CommitTime()
+			since = &commitTime
+		}
+	}
+
+	candidates := make(map[plumbing.Hash]commitgraph.CommitNode)
+	truncated := make(map[plumbing.Hash]commitgraph.CommitNode)
+	remainingSlop := slop
+	for queue.Len() > 0 {
+		if !queue.hasInteresting() {
+			if remainingSlop == 0 {
+				break
+			}
+			remainingSlop--
+		}
+
+		node := queue.pop()
+		flag := flags[node.ID()]
+		if flag == reachableFromNew {
+			candidates[node.ID()] = node
+		}
+
+		for i := 0; i < node.NumParents(); i++ {
+			parent, err := node.ParentNode(i)
+			if shallow && errors.Is(err, plumbing.ErrObjectNotFound) {
+				// the shallow boundary is reached, the parents were never fetched
+				truncated[node.ID()] = node
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if depth, ok := depths[parent.ID()]; !ok || depth > depths[node.ID()]+1 {
+				depths[parent.ID()] = depths[node.ID()] + 1
+			}
+			push(parent, flag)
+		}
+	}
+
+	commits := make(map[plumbing.Hash]commitgraph.CommitNode, len(candidates))
+	for hash, node := range candidates {
+		if flags[hash]&reachableFromOld == 0 {
+			commits[hash] = node
+		}
+	}
+
+	// a boundary is only a problem, if the range might continue behind it
+	depth := 0
+	for hash := range truncated {
+		if _, inRange := commits[hash]; inRange && depths[hash] > depth {
+			depth = depths[hash]
+		}
+	}
+	if depth > 0 || oldMissing {
+		return nil, &ShallowHistoryError{Depth: depth, Since: since}
+	}
+
+	return commits, nil
+}
+
+// topoOrder sorts the range from newest to oldest, so that every Commit comes after all of its children in the range.
+// Among the Commits, whose children have all been returned, the first parent is followed first,
+// so that Commits with the same commit time are still returned in a deterministic order.
+func topoOrder(
+	newHash plumbing.Hash,
+	commits map[plumbing.Hash]commitgraph.CommitNode,
+	firstParent bool,
+) []commitgraph.CommitNode {
+	hashes := childrenFirst(newHash, func(hash plumbing.Hash) ([]plumbing.Hash, bool) {
+		node, ok := commits[hash]
+		if !ok {
+			return nil, false
+		}
+		return node.ParentHashes(), true
+	}, firstParent)
+
+	ordered := make([]commitgraph.CommitNode, 0, len(hashes))
+	for _, hash := range hashes {
+		ordered = append(ordered, commits[hash])
+	}
+	return ordered
+}
+
+// childrenFirst sorts a range of Commits topologically like git log --topo-order and returns them newest first.
+// A Commit is only returned after all of its children in the range, and the first parent is followed first.
+// The parents function returns false for Commits outside of the range.
+func childrenFirst[T comparable](start T, parents func(commit T) ([]T, bool), firstParent bool) []T {
+	rangeParents := func(commit T) []T {
+		commitParents, _ := parents(commit)
+		if firstParent && len(commitParents) > 1 {
+			commitParents = commitParents[:1]
+		}
+		return commitParents
+	}
+
+	// count the children of every Commit in the range
+	children := make(map[T]int)
+	if _, inRange := parents(start); !inRange {
+		return nil
+	}
+	seen := map[T]bool{start: true}
+	stack := []T{start}
+	for len(stack) > 0 {
+		commit := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, parent := range rangeParents(commit) {
+			if _, inRange := parents(parent); !inRange {
+				continue
+			}
+			children[parent]++
+			if !seen[parent] {
+				seen[parent] = true
+				stack = append(stack, parent)
+			}
+		}
+	}
+
+	ordered := make([]T, 0, len(seen))
+	stack = []T{start}
+	for len(stack) > 0 {
+		commit := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		ordered = append(ordered, commit)
+
+		commitParents := rangeParents(commit)
+		for i := len(commitParents) - 1; i >= 0; i-- {
+			parent := commitParents[i]
+			if _, inRange := parents(parent); !inRange {
+				continue
+			}
+			children[parent]--
+			if children[parent] == 0 {
+				stack = append(stack, parent)
+			}
+		}
+	}
+
+	return ordered
+}
+
+// touches reports whether a Commit changed any path accepted by the filter.
+// Like git's history simplification, merge commits only count if they differ from all of their parents,
+// because the branch commits already carry the changes. In first parent mode merge commits are compared to their first parent only.
+func touches(node commitgraph.CommitNode, filter func(path string) bool, firstParent bool) (bool, error) {
+	tree, err := node.Tree()
+	if err != nil {
+		return false, err
+	}
+
+	if node.NumParents() == 0 {
+		return changed(nil, tree, filter)
+	}
+
+	numParents := node.NumParents()
+	if firstParent {
+		numParents = 1
+	}
+
+	for i := 0; i < numParents; i++ {
+		parent, err := node.ParentNode(i)
+		if err != nil {
+			return false, err
+		}
+
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return false, err
+		}
+
+		differs, err := changed(parentTree, tree, filter)
+		if err != nil {
+			return false, err
+		}
+
+		if !differs {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func changed(from *object.Tree, to *object.Tree, filter func(path string) bool) (bool, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return false, err
+	}
+
+	for _, change := range changes {
+		if filter(change.From.Name) || filter(change.To.Name) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// queuedCommit is a CommitNode waiting to be walked, together with its flag at the time it was queued.
+type queuedCommit struct {
+	node commitgraph.CommitNode
+	flag ancestryFlag
+}
+
+// commitQueue is a max heap of CommitNodes ordered by commit time.
+// For equal commit times, Commits reachable from the older side come first, so that they are marked before they are considered part of the range.
+type commitQueue struct {
+	commits []queuedCommit
+	flags   map[plumbing.Hash]ancestryFlag
+}
+
+var _ heap.Interface = &commitQueue{}
+
+func (queue *commitQueue) Len() int {
+	return len(queue.commits)
+}
+
+func (queue *commitQueue) Less(i, j int) bool {
+	timeI := queue.commits[i].node.CommitTime()
+	timeJ := queue.commits[j].node.CommitTime()
+	if !timeI.Equal(timeJ) {
+		return timeI.After(timeJ)
+	}
+
+	oldI := queue.commits[i].flag&reachableFromOld != 0
+	oldJ := queue.commits[j].flag&reachableFromOld != 0
+	return oldI && !oldJ
+}
+
+func (queue *commitQueue) Swap(i, j int) {
+	queue.commits[i], queue.commits[j] = queue.commits[j], queue.commits[i]
+}
+
+func (queue *commitQueue) Push(commit any) {
+	if queued, ok := commit.(queuedCommit); ok {
+		queue.commits = append(queue.commits, queued)
+	}
+}
+
+func (queue *commitQueue) Pop() any {
+	last := len(queue.commits) - 1
+	commit := queue.commits[last]
+	queue.commits = queue.commits[:last]
+	return commit
+}
+
+func (queue *commitQueue) pop() commitgraph.CommitNode {
+	commit, _ := heap.Pop(queue).(queuedCommit)
+	return commit.node
+}
+
+// hasInteresting reports whether any queued Commit is not yet known to be reachable from the older side.
+func (queue *commitQueue) hasInteresting() bool {
+	for _, commit := range queue.commits {
+		if queue.flags[commit.node.ID()]&reachableFromOld == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitRange(t *testing.T) {
+	index, closeIndex := repository.(GoGitRepository).commitNodeIndex()
+	defer closeIndex()
+
+	newHash := plumbing.NewHash(commits[lenCommits-1].Hash)
+	oldHash := plumbing.NewHash(commits[lenCommits-4].Hash)
+	commitsInRange, err := commitRange(index, newHash, oldHash, false)
+	assert.NoError(t, err)
+
+	assert.Len(t, commitsInRange, 3)
+	for _, commit := range commits[lenCommits-3:] {
+		assert.Contains(t, commitsInRange, plumbing.NewHash(commit.Hash))
+	}
+}
+
+func TestCommitRange_NoOldHash(t *testing.T) {
+	index, closeIndex := repository.(GoGitRepository).commitNodeIndex()
+	defer closeIndex()
+
+	newHash := plumbing.NewHash(commits[lenCommits-1].Hash)
+	commitsInRange, err := commitRange(index, newHash, plumbing.ZeroHash, false)
+	assert.NoError(t, err)
+	assert.Len(t, commitsInRange, lenCommits)
+}
+
+func TestCommitRange_NewIsAncestorOfOld(t *testing.T) {
+	index, closeIndex := repository.(GoGitRepository).commitNodeIndex()
+	defer closeIndex()
+
+	newHash := plumbing.NewHash(commits[2].Hash)
+	oldHash := plumbing.NewHash(commits[lenCommits-1].Hash)
+	commitsInRange, err := commitRange(index, newHash, oldHash, false)
+	assert.NoError(t, err)
+	assert.Len(t, commitsInRange, 0)
+}
+
+func TestTopoOrder_Merge(t *testing.T) {
+	repository, mergeCommits := newMergeRepo(t.TempDir())
+	index, closeIndex := repository.commitNodeIndex()
+	defer closeIndex()
+
+	newHash := plumbing.NewHash(mergeCommits["M"].Hash)
+	commitsInRange, err := commitRange(index, newHash, plumbing.ZeroHash, false)
+	assert.NoError(t, err)
+
+	var hashes []string
+	for _, node := range topoOrder(newHash, commitsInRange, false) {
+		hashes = append(hashes, node.ID().String())
+	}
+	assert.Equal(t, []string{
+		mergeCommits["M"].Hash,
+		mergeCommits["D"].Hash,
+		mergeCommits["C"].Hash,
+		mergeCommits["B"].Hash,
+		mergeCommits["A"].Hash,
+	}, hashes)
+
+	hashes = nil
+	for _, node := range topoOrder(newHash, commitsInRange, true) {
+		hashes = append(hashes, node.ID().String())
+	}
+	assert.Equal(t, []string{
+		mergeCommits["M"].Hash,
+		mergeCommits["D"].Hash,
+		mergeCommits["A"].Hash,
+	}, hashes)
+}
@@ -0,0 +1,67 @@
+package monoreleaser
+
+import (
+	"fmt"
+	"time"
+)
+
+// A ReleaseBackfiller creates releases at external sources for Tags, which have been created without releasing them there,
+// e.g. after migrating a repository.
+type ReleaseBackfiller interface {
+	// ReleasedTags returns the names of all Tags released at the external source, including drafts.
+	ReleasedTags() (map[string]bool, error)
+	// CreateRelease releases an existing Tag with its Changelog.
+	// Only the Module, the TagModule and the Provider options are used, as the Tag already exists.
+	CreateRelease(tag Tag, changelog Changelog, opts ReleaseOptions) error
+}
+
+// Optional parameters for backfilling releases.
+type BackfillOptions struct {
+	// When DryRun is set, the missing releases are determined, but not created.
+	DryRun bool
+	// Interval is waited between the creation of two releases, as GitHub limits the rate of content creating requests.
+	// If this option is not set, releases are created without waiting.
+	Interval time.Duration
+	// Provider options of the created releases.
+	// If MakeLatest is not set, MakeLatestFalse will be used, so that releases of old Tags do not become the latest release.
+	Provider ProviderOptions
+}
+
+// Backfill creates the releases of past releases missing at the external source in the given order and returns their Tags.
+// The releases should be ordered oldest first, which is the reverse order of ListReleases, so that they are listed in the right order.
+// Existing releases are skipped, so that an interrupted backfill resumes where it stopped, when it is run again.
+func Backfill(backfiller ReleaseBackfiller, releases []PastRelease, opts BackfillOptions) ([]Tag, error) {
+	if opts.Provider.MakeLatest == "" {
+		opts.Provider.MakeLatest = MakeLatestFalse
+	}
+
+	released, err := backfiller.ReleasedTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var created []Tag
+	for _, release := range releases {
+		if released[release.Tag.Name] {
+			continue
+		}
+
+		changelog, err := release.Changelog()
+		if err != nil {
+			return created, fmt.Errorf("%s: %w", release.Tag.Name, err)
+		}
+
+		if !opts.DryRun {
+			if len(created) != 0 && opts.Interval > 0 {
+				time.Sleep(opts.Interval)
+			}
+			releaseOpts := ReleaseOptions{Module: release.Module, TagModule: release.TagModule, Provider: opts.Provider}
+			if err := backfiller.CreateRelease(release.Tag, changelog, releaseOpts); err != nil {
+				return created, fmt.Errorf("%s: %w", release.Tag.Name, err)
+			}
+		}
+		created = append(created, release.Tag)
+	}
+
+	return created, nil
+}
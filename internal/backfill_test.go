@@ -0,0 +1,193 @@
+package monoreleaser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redirectReleaser sends the requests of the releaser to a test server.
+func redirectReleaser(t *testing.T, releaser *GithubReleaser, handler http.HandlerFunc) *httptest.Server {
+	ts := httptest.NewServer(handler)
+	serverUrl, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	releaser.releaseClient.url.Host = serverUrl.Host
+	releaser.releaseClient.url.Scheme = serverUrl.Scheme
+	releaser.assetClient.url.Host = serverUrl.Host
+	releaser.assetClient.url.Scheme = serverUrl.Scheme
+	return ts
+}
+
+func TestGithubReleaser_ReleasedTags(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/kharf/myrepo/releases", r.URL.Path)
+		assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+
+		size := 100
+		if r.URL.Query().Get("page") == "2" {
+			size = 1
+		}
+		w.Write([]byte("["))
+		for i := 0; i < size; i++ {
+			if i != 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"tag_name": "v%s.%d.0"}`, r.URL.Query().Get("page"), i)
+		}
+		w.Write([]byte("]"))
+	})
+	defer ts.Close()
+
+	released, err := releaser.ReleasedTags()
+	require.NoError(t, err)
+	assert.Len(t, released, 101)
+	assert.True(t, released["v1.99.0"])
+	assert.True(t, released["v2.0.0"])
+}
+
+func TestGithubReleaser_CreateRelease(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	var body map[string]any
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"id": 1}`))
+	})
+	defer ts.Close()
+
+	nameTemplate, err := NewReleaseNameTemplate("{{.Module}} {{.Version}}")
+	require.NoError(t, err)
+	// configured Modules are named like they are tagged
+	tag := Tag{Name: "payments-api/v1.0.0", Version: "v1.0.0"}
+	opts := ReleaseOptions{
+		Module:    "services/payments/api",
+		TagModule: "payments-api",
+		Provider:  ProviderOptions{MakeLatest: MakeLatestFalse, NameTemplate: nameTemplate},
+	}
+	require.NoError(t, releaser.CreateRelease(tag, "changelog", opts))
+	assert.Equal(t, map[string]any{
+		"tag_name":    "payments-api/v1.0.0",
+		"body":        "changelog",
+		"name":        "payments-api 1.0.0",
+		"make_latest": "false",
+	}, body)
+}
+
+func TestGithubReleaser_RateLimited(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		header   http.Header
+		status   int
+		expected time.Time
+	}{
+		{
+			header:   http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(reset.Unix(), 10)}},
+			status:   http.StatusForbidden,
+			expected: reset,
+		},
+		{
+			header:   http.Header{"Retry-After": {"60"}},
+			status:   http.StatusTooManyRequests,
+			expected: time.Now().Add(time.Minute),
+		},
+	}
+
+	for _, testCase := range testCases {
+		ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+			for key, values := range testCase.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(testCase.status)
+		})
+
+		_, err := releaser.ReleasedTags()
+		assert.ErrorIs(t, err, ErrRateLimited)
+		var rateLimitErr *RateLimitError
+		require.True(t, errors.As(err, &rateLimitErr))
+		assert.WithinDuration(t, testCase.expected, rateLimitErr.Reset, 5*time.Second)
+		ts.Close()
+	}
+
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	defer ts.Close()
+	_, err := releaser.ReleasedTags()
+	assert.ErrorIs(t, err, ErrRequestUnsuccessful)
+	assert.NotErrorIs(t, err, ErrRateLimited)
+}
+
+// releaseBackfiller is a ReleaseBackfiller keeping the Changelogs and options of the releases by Tag name,
+// which fails creating the release of failOn.
+type releaseBackfiller struct {
+	changelogs map[string]Changelog
+	opts       map[string]ReleaseOptions
+	failOn     string
+}
+
+func (backfiller *releaseBackfiller) ReleasedTags() (map[string]bool, error) {
+	released := make(map[string]bool, len(backfiller.changelogs))
+	for tag := range backfiller.changelogs {
+		released[tag] = true
+	}
+	return released, nil
+}
+
+func (backfiller *releaseBackfiller) CreateRelease(tag Tag, changelog Changelog, opts ReleaseOptions) error {
+	if tag.Name == backfiller.failOn {
+		return &RateLimitError{}
+	}
+	backfiller.changelogs[tag.Name] = changelog
+	backfiller.opts[tag.Name] = opts
+	return nil
+}
+
+func TestBackfill(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		releases, err := ListReleases(repository, Module{Path: ""}, PastReleaseOptions{})
+		require.NoError(t, err)
+		// oldest first
+		releases = []PastRelease{releases[3], releases[2], releases[1], releases[0]}
+
+		backfiller := &releaseBackfiller{
+			changelogs: map[string]Changelog{"v1.9.0": "released"},
+			opts:       map[string]ReleaseOptions{},
+			failOn:     "v1.10.0",
+		}
+		created, err := Backfill(backfiller, releases, BackfillOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, []Tag{releases[0].Tag, releases[1].Tag, releases[3].Tag}, created)
+		assert.Len(t, backfiller.changelogs, 1)
+
+		created, err = Backfill(backfiller, releases, BackfillOptions{})
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, []Tag{releases[0].Tag, releases[1].Tag}, created)
+
+		// old Tags do not become the latest release
+		assert.Equal(t, ReleaseOptions{Provider: ProviderOptions{MakeLatest: MakeLatestFalse}}, backfiller.opts[releases[0].Tag.Name])
+
+		// resumes where it stopped
+		backfiller.failOn = ""
+		created, err = Backfill(backfiller, releases, BackfillOptions{Provider: ProviderOptions{Draft: true, MakeLatest: MakeLatestLegacy}})
+		require.NoError(t, err)
+		assert.Equal(t, []Tag{releases[3].Tag}, created)
+		assert.Equal(t, ProviderOptions{Draft: true, MakeLatest: MakeLatestLegacy}, backfiller.opts["v1.10.0"].Provider)
+
+		changelog, err := releases[3].Changelog()
+		require.NoError(t, err)
+		assert.Equal(t, changelog, backfiller.changelogs["v1.10.0"])
+		assert.Equal(t, Changelog("released"), backfiller.changelogs["v1.9.0"])
+	})
+}
@@ -0,0 +1,234 @@
+package monoreleaser
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var ErrFileNotFound = errors.New("file not found")
+
+func (repo GoGitRepository) ResolveRevision(revision string) (string, error) {
+	hash, err := repo.repository.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+func (repo GoGitRepository) CommitDate(revision string) (time.Time, error) {
+	hash, err := repo.repository.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	commit, err := repo.repository.CommitObject(*hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return commit.Committer.When, nil
+}
+
+func (repo GoGitRepository) ReadFile(revision string, file string) ([]byte, error) {
+	hash, err := repo.repository.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := repo.repository.CommitObject(*hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	treeFile, err := tree.File(file)
+	if errors.Is(err, object.ErrFileNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := treeFile.Contents()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(contents), nil
+}
+
+func (repo GoGitRepository) CommitFiles(branch string, message string, files map[string][]byte) (*Commit, error) {
+	signature, err := repo.signature()
+	if err != nil {
+		return nil, err
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	var parents []plumbing.Hash
+	var baseTree *object.Tree
+	ref, err := repo.repository.Reference(branchRef, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		ref, err = repo.repository.Reference(plumbing.NewRemoteReferenceName("origin", branch), true)
+	}
+	switch {
+	case errors.Is(err, plumbing.ErrReferenceNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		parent, err := repo.repository.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, err
+		}
+		baseTree, err = parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, parent.Hash)
+	}
+
+	treeHash, err := repo.writeTree(baseTree, files)
+	if err != nil {
+		return nil, err
+	}
+
+	commit := &object.Commit{
+		Author:       *signature,
+		Committer:    *signature,
+		Message:      message,
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	}
+	hash, err := repo.storeObject(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.repository.Storer.SetReference(plumbing.NewHashReference(branchRef, hash)); err != nil {
+		return nil, err
+	}
+
+	return &Commit{
+		Hash:    hash.String(),
+		Message: message,
+	}, nil
+}
+
+// signature returns the configured user like git commit does.
+func (repo GoGitRepository) signature() (*object.Signature, error) {
+	cfg, err := repo.repository.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.User.Name == "" || cfg.User.Email == "" {
+		return nil, git.ErrMissingAuthor
+	}
+
+	return &object.Signature{
+		Name:  cfg.User.Name,
+		Email: cfg.User.Email,
+		When:  time.Now(),
+	}, nil
+}
+
+type encodableObject interface {
+	Encode(plumbing.EncodedObject) error
+}
+
+func (repo GoGitRepository) storeObject(obj encodableObject) (plumbing.Hash, error) {
+	encoded := repo.repository.Storer.NewEncodedObject()
+	if err := obj.Encode(encoded); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.repository.Storer.SetEncodedObject(encoded)
+}
+
+// writeTree stores a tree, which consists of the base tree with the files written into it.
+// File paths are relative to the tree and always use forward slashes.
+func (repo GoGitRepository) writeTree(base *object.Tree, files map[string][]byte) (plumbing.Hash, error) {
+	entries := make(map[string]object.TreeEntry)
+	if base != nil {
+		for _, entry := range base.Entries {
+			entries[entry.Name] = entry
+		}
+	}
+
+	subdirs := make(map[string]map[string][]byte)
+	for file, content := range files {
+		dir, rest, nested := strings.Cut(file, "/")
+		if nested {
+			if subdirs[dir] == nil {
+				subdirs[dir] = make(map[string][]byte)
+			}
+			subdirs[dir][rest] = content
+			continue
+		}
+
+		blob := repo.repository.Storer.NewEncodedObject()
+		blob.SetType(plumbing.BlobObject)
+		writer, err := blob.Writer()
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if _, err := writer.Write(content); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if err := writer.Close(); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		hash, err := repo.repository.Storer.SetEncodedObject(blob)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		mode := filemode.Regular
+		if entry, ok := entries[file]; ok && entry.Mode == filemode.Executable {
+			mode = entry.Mode
+		}
+		entries[file] = object.TreeEntry{Name: file, Mode: mode, Hash: hash}
+	}
+
+	for dir, dirFiles := range subdirs {
+		var subtree *object.Tree
+		if entry, ok := entries[dir]; ok && entry.Mode == filemode.Dir {
+			var err error
+			subtree, err = repo.repository.TreeObject(entry.Hash)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+		}
+
+		hash, err := repo.writeTree(subtree, dirFiles)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries[dir] = object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: hash}
+	}
+
+	tree := &object.Tree{Entries: make([]object.TreeEntry, 0, len(entries))}
+	for _, entry := range entries {
+		tree.Entries = append(tree.Entries, entry)
+	}
+	// git sorts directories as if their names ended with a slash
+	sort.Slice(tree.Entries, func(i, j int) bool {
+		return treeEntrySortName(tree.Entries[i]) < treeEntrySortName(tree.Entries[j])
+	})
+
+	return repo.storeObject(tree)
+}
+
+func treeEntrySortName(entry object.TreeEntry) string {
+	if entry.Mode == filemode.Dir {
+		return entry.Name + "/"
+	}
+	return entry.Name
+}
@@ -0,0 +1,84 @@
+package monoreleaser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		content, err := repository.ReadFile("HEAD", "subdir/0")
+		require.NoError(t, err)
+		assert.Empty(t, content)
+
+		_, err = repository.ReadFile("HEAD", "subdir/missing")
+		assert.ErrorIs(t, err, ErrFileNotFound)
+
+		_, err = repository.ReadFile("missing-branch", "subdir/0")
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
+func TestCommitDate(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		date, err := repository.CommitDate(commits[0].Hash)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), date, time.Hour)
+
+		_, err = repository.CommitDate("missing-branch")
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+}
+
+func TestCommitFiles(t *testing.T) {
+	var dir string
+	forEachImplementation(t, func(t *testing.T) string {
+		dir = versionFileRepo(t)
+		return dir
+	}, func(t *testing.T, repository Repository) {
+		head, err := repository.Head()
+		require.NoError(t, err)
+
+		// an orphan branch is created, if it does not exist
+		first, err := repository.CommitFiles("gh-pages", "first", map[string][]byte{
+			"index.yaml":        []byte("first"),
+			"charts/index.yaml": []byte("nested"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "first", first.Message)
+
+		second, err := repository.CommitFiles("gh-pages", "second", map[string][]byte{
+			"index.yaml":           []byte("second"),
+			"charts/api-1.0.0.tgz": []byte("chart"),
+		})
+		require.NoError(t, err)
+
+		for file, expected := range map[string]string{
+			"index.yaml":           "second",
+			"charts/index.yaml":    "nested",
+			"charts/api-1.0.0.tgz": "chart",
+		} {
+			content, err := repository.ReadFile("gh-pages", file)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(content), file)
+		}
+
+		content, err := repository.ReadFile("gh-pages~1", "index.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(content))
+		assert.NotEqual(t, first.Hash, second.Hash)
+
+		// neither the current branch nor the working tree are touched
+		newHead, err := repository.Head()
+		require.NoError(t, err)
+		assert.Equal(t, head, newHead)
+		_, err = os.Stat(filepath.Join(dir, "index.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
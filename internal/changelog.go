@@ -13,6 +13,7 @@ const (
 	Rocket        Emoji = "\U0001F680"
 	Bug           Emoji = "\U0001F41B"
 	Package       Emoji = "\U0001f4E6"
+	Rewind        Emoji = "\u23EA"
 )
 
 // A markdown formatted Changelog.
@@ -26,6 +27,7 @@ func GenerateChangelog(changes []Change) (Changelog, error) {
 	var major strings.Builder
 	var minor strings.Builder
 	var patches strings.Builder
+	var reverts strings.Builder
 	var unknowns strings.Builder
 
 	for _, change := range changes {
@@ -43,6 +45,10 @@ func GenerateChangelog(changes []Change) (Changelog, error) {
 			if err := write(string(Bug)+" Patch", &patches, change); err != nil {
 				return "", err
 			}
+		case Revert:
+			if err := write(string(Rewind)+" Reverts", &reverts, change); err != nil {
+				return "", err
+			}
 		case Unknown:
 			if err := write(string(Package)+" Uncategorized", &unknowns, change); err != nil {
 				return "", err
@@ -63,6 +69,12 @@ func GenerateChangelog(changes []Change) (Changelog, error) {
 	if _, err := sb.WriteString(fmt.Sprintf("\n%s", patches.String())); err != nil {
 		return "", err
 	}
+	// reverts of already released changes are rare, so the section is omitted when empty
+	if reverts.Len() != 0 {
+		if _, err := sb.WriteString(fmt.Sprintf("\n%s", reverts.String())); err != nil {
+			return "", err
+		}
+	}
 	if _, err := sb.WriteString(fmt.Sprintf("\n%s", unknowns.String())); err != nil {
 		return "", err
 	}
@@ -45,3 +45,26 @@ func TestGenerateChangelog(t *testing.T) {
 
 	assert.Equal(t, expected, changelog)
 }
+
+func TestGenerateChangelog_Reverts(t *testing.T) {
+	changes := Extract([]*Commit{
+		{Hash: "3333333333", Message: "Revert \"feat: X\"\n\nThis reverts commit 1111111111."},
+		{Hash: "2222222222", Message: "fix: Y"},
+	})
+	changelog, err := GenerateChangelog(changes)
+	assert.NoError(t, err)
+	expected := Changelog(`# What's Changed
+
+
+## 🐛 Patch
+- fix: Y
+
+## ⏪ Reverts
+- Revert "feat: X"
+	
+	This reverts commit 1111111111.
+
+`)
+
+	assert.Equal(t, expected, changelog)
+}
@@ -0,0 +1,218 @@
+package monoreleaser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/mod/modfile"
+)
+
+var ErrDependencyCycle = errors.New("modules depend on each other")
+
+// A DependencyGraph maps every Module (Path) to the Modules it depends on.
+type DependencyGraph map[string][]string
+
+// packageJSON contains the parts of a package.json relevant for dependencies between Modules.
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// BuildDependencyGraph reads the manifests of the Modules and determines which Modules depend on each other.
+// A Go Module depends on another one, if it requires its module path or replaces a module with its directory.
+// A Node.js Module depends on another one, if it has a dependency on its package name, e.g. a workspace dependency.
+// Root is the directory of the repository. If it is empty, the current directory will be used.
+func BuildDependencyGraph(fsys afero.Fs, root string, modules []Module) (DependencyGraph, error) {
+	if root == "" {
+		root = "."
+	}
+
+	goModules := make(map[string]*modfile.File)
+	packages := make(map[string]*packageJSON)
+	goPaths := make(map[string]string)
+	packageNames := make(map[string]string)
+	dirs := make(map[string]bool)
+	for _, module := range modules {
+		dirs[module.Path] = true
+		for _, marker := range module.Markers {
+			switch marker {
+			case "go.mod":
+				goModule, err := readGoMod(fsys, root, module.Path)
+				if err != nil {
+					return nil, err
+				}
+				goModules[module.Path] = goModule
+				if goModule.Module != nil {
+					goPaths[goModule.Module.Mod.Path] = module.Path
+				}
+			case "package.json":
+				pkg, err := readPackageJSON(fsys, root, module.Path)
+				if err != nil {
+					return nil, err
+				}
+				packages[module.Path] = pkg
+				if pkg.Name != "" {
+					packageNames[pkg.Name] = module.Path
+				}
+			}
+		}
+	}
+
+	graph := make(DependencyGraph, len(modules))
+	for _, module := range modules {
+		dependencies := make(map[string]bool)
+		if goModule, ok := goModules[module.Path]; ok {
+			for _, require := range goModule.Require {
+				if dependency, ok := goPaths[require.Mod.Path]; ok {
+					dependencies[dependency] = true
+				}
+			}
+			for _, replace := range goModule.Replace {
+				if !modfile.IsDirectoryPath(replace.New.Path) {
+					continue
+				}
+				dependency := moduleJoin(module.Path, replace.New.Path)
+				if dirs[dependency] {
+					dependencies[dependency] = true
+				}
+			}
+		}
+
+		if pkg, ok := packages[module.Path]; ok {
+			for _, deps := range []map[string]string{
+				pkg.Dependencies,
+				pkg.DevDependencies,
+				pkg.PeerDependencies,
+				pkg.OptionalDependencies,
+			} {
+				for name := range deps {
+					if dependency, ok := packageNames[name]; ok {
+						dependencies[dependency] = true
+					}
+				}
+			}
+		}
+
+		delete(dependencies, module.Path)
+		graph[module.Path] = sortedKeys(dependencies)
+	}
+
+	return graph, nil
+}
+
+func readGoMod(fsys afero.Fs, root string, module string) (*modfile.File, error) {
+	file := filepath.Join(root, module, "go.mod")
+	content, err := afero.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return modfile.Parse(file, content, nil)
+}
+
+func readPackageJSON(fsys afero.Fs, root string, module string) (*packageJSON, error) {
+	file := filepath.Join(root, module, "package.json")
+	content, err := afero.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return &pkg, nil
+}
+
+// moduleJoin resolves a relative directory of a Module to a Module Path.
+func moduleJoin(module string, dir string) string {
+	joined := path.Join(module, filepath.ToSlash(dir))
+	if joined == "." {
+		return ""
+	}
+	return joined
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Order sorts all Modules of the graph topologically, so that every Module comes after its dependencies.
+// Independent Modules are sorted by Path, so that the order is deterministic.
+// An ErrDependencyCycle is returned, if Modules depend on each other.
+func (graph DependencyGraph) Order() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	modules := make([]string, 0, len(graph))
+	for module := range graph {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	state := make(map[string]int, len(graph))
+	ordered := make([]string, 0, len(graph))
+	var stack []string
+	var visit func(module string) error
+	visit = func(module string) error {
+		switch state[module] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append([]string{}, stack[indexOf(stack, module):]...)
+			cycle = append(cycle, module)
+			for i := range cycle {
+				cycle[i] = ModuleName(cycle[i])
+			}
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+
+		state[module] = visiting
+		stack = append(stack, module)
+		for _, dependency := range graph[module] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[module] = visited
+		ordered = append(ordered, module)
+
+		return nil
+	}
+
+	for _, module := range modules {
+		if err := visit(module); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+func indexOf(modules []string, module string) int {
+	for i := range modules {
+		if modules[i] == module {
+			return i
+		}
+	}
+	return -1
+}
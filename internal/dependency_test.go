@@ -0,0 +1,110 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDependencyFs(t *testing.T, files map[string]string) (afero.Fs, []Module) {
+	fs := afero.NewMemMapFs()
+	for file, content := range files {
+		require.NoError(t, afero.WriteFile(fs, file, []byte(content), 0o644))
+	}
+
+	modules, err := DiscoverModules(fs, DiscoverOptions{})
+	require.NoError(t, err)
+	return fs, modules
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	fs, modules := newDependencyFs(t, map[string]string{
+		"libs/auth/go.mod": `module github.com/acme/repo/libs/auth
+
+go 1.20
+`,
+		"libs/log/go.mod": `module github.com/acme/repo/libs/log
+
+go 1.20
+`,
+		"services/api/go.mod": `module github.com/acme/repo/services/api
+
+go 1.20
+
+require (
+	github.com/acme/repo/libs/auth v1.3.0
+	github.com/stretchr/testify v1.10.0
+)
+`,
+		"services/worker/go.mod": `module github.com/acme/repo/services/worker
+
+go 1.20
+
+require example.com/logging v0.0.0
+
+replace example.com/logging => ../../libs/log
+`,
+		"web/ui/package.json": `{
+  "name": "@acme/ui",
+  "dependencies": {"react": "^18.0.0"}
+}`,
+		"web/app/package.json": `{
+  "name": "@acme/app",
+  "dependencies": {"@acme/ui": "workspace:*"},
+  "devDependencies": {"@acme/config": "^1.0.0"}
+}`,
+		"web/config/package.json": `{
+  "name": "@acme/config"
+}`,
+	})
+
+	graph, err := BuildDependencyGraph(fs, "", modules)
+	require.NoError(t, err)
+	assert.Equal(t, DependencyGraph{
+		"libs/auth":       {},
+		"libs/log":        {},
+		"services/api":    {"libs/auth"},
+		"services/worker": {"libs/log"},
+		"web/app":         {"web/config", "web/ui"},
+		"web/config":      {},
+		"web/ui":          {},
+	}, graph)
+}
+
+func TestBuildDependencyGraph_InvalidPackageJSON(t *testing.T) {
+	fs, modules := newDependencyFs(t, map[string]string{
+		"web/ui/package.json": `{`,
+	})
+
+	_, err := BuildDependencyGraph(fs, "", modules)
+	assert.Error(t, err)
+}
+
+func TestDependencyGraph_Order(t *testing.T) {
+	graph := DependencyGraph{
+		"services/api":    {"libs/auth", "libs/log"},
+		"services/worker": {"libs/log"},
+		"libs/auth":       {"libs/log"},
+		"libs/log":        {},
+		"":                {},
+	}
+
+	order, err := graph.Order()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "libs/log", "libs/auth", "services/api", "services/worker"}, order)
+}
+
+func TestDependencyGraph_Order_Cycle(t *testing.T) {
+	graph := DependencyGraph{
+		"libs/a": {"libs/b"},
+		"libs/b": {"libs/c"},
+		"libs/c": {"libs/a"},
+		"libs/d": {},
+	}
+
+	_, err := graph.Order()
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	assert.EqualError(t, err, "modules depend on each other: libs/a -> libs/b -> libs/c -> libs/a")
+}
@@ -0,0 +1,111 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var ErrReleaseNotFound = errors.New("release not found")
+
+// A ReleaseEditor updates the Changelogs of existing releases at external sources like scms.
+type ReleaseEditor interface {
+	// Changelog returns the Changelog of the release of a Tag.
+	// The error is ErrReleaseNotFound, if the Tag has not been released at the external source.
+	Changelog(tag Tag) (Changelog, error)
+	// EditChangelog replaces the Changelog of the release of a Tag.
+	EditChangelog(tag Tag, changelog Changelog) error
+}
+
+// A ChangelogEdit is the regenerated Changelog of a past release.
+type ChangelogEdit struct {
+	Tag Tag
+	// Current is the Changelog of the release at the external source.
+	Current Changelog
+	// Changelog is regenerated from the Commits of the release.
+	Changelog Changelog
+	// Missing means, that the Tag has not been released at the external source, so that nothing was edited.
+	Missing bool
+	// Lost are the entries of the current Changelog, which the regenerated one lacks, e.g. bumped dependencies,
+	// which are not part of the history. Releases losing entries are not edited.
+	Lost []string
+}
+
+// Changed reports whether the regenerated Changelog differs from the current one.
+func (edit ChangelogEdit) Changed() bool {
+	return !edit.Missing && edit.Current != edit.Changelog
+}
+
+// Diff returns the unified diff from the current to the regenerated Changelog.
+func (edit ChangelogEdit) Diff() (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(edit.Current)),
+		B:        difflib.SplitLines(string(edit.Changelog)),
+		FromFile: edit.Tag.Name + " (current)",
+		ToFile:   edit.Tag.Name + " (regenerated)",
+		Context:  3,
+	})
+}
+
+// lostEntries returns the entries of the current Changelog, which the regenerated one lacks.
+func lostEntries(current Changelog, regenerated Changelog) []string {
+	entries := make(map[string]bool)
+	for _, line := range strings.Split(string(regenerated), "\n") {
+		if strings.HasPrefix(line, "- ") {
+			entries[line] = true
+		}
+	}
+
+	var lost []string
+	for _, line := range strings.Split(string(current), "\n") {
+		if strings.HasPrefix(line, "- ") && !entries[line] {
+			lost = append(lost, strings.TrimPrefix(line, "- "))
+		}
+	}
+	return lost
+}
+
+// Optional parameters for editing the Changelogs of past releases.
+type EditOptions struct {
+	// When DryRun is set, the Changelogs are regenerated and compared, but not updated.
+	DryRun bool
+}
+
+// EditChangelogs regenerates the Changelogs of past releases and updates the changed ones at the external source.
+// Releases, which do not exist at the external source, are skipped and reported as Missing.
+// Releases, whose current Changelog has entries the regenerated one lacks, are skipped and report them as Lost.
+func EditChangelogs(editor ReleaseEditor, releases []PastRelease, opts EditOptions) ([]ChangelogEdit, error) {
+	edits := make([]ChangelogEdit, 0, len(releases))
+	for _, release := range releases {
+		edit := ChangelogEdit{Tag: release.Tag}
+
+		changelog, err := release.Changelog()
+		if err != nil {
+			return edits, fmt.Errorf("%s: %w", release.Tag.Name, err)
+		}
+		edit.Changelog = changelog
+
+		current, err := editor.Changelog(release.Tag)
+		if errors.Is(err, ErrReleaseNotFound) {
+			edit.Missing = true
+			edits = append(edits, edit)
+			continue
+		}
+		if err != nil {
+			return edits, fmt.Errorf("%s: %w", release.Tag.Name, err)
+		}
+		edit.Current = current
+		edit.Lost = lostEntries(current, changelog)
+
+		if edit.Changed() && len(edit.Lost) == 0 && !opts.DryRun {
+			if err := editor.EditChangelog(release.Tag, changelog); err != nil {
+				return edits, fmt.Errorf("%s: %w", release.Tag.Name, err)
+			}
+		}
+		edits = append(edits, edit)
+	}
+
+	return edits, nil
+}
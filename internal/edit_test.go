@@ -0,0 +1,109 @@
+package monoreleaser
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGithubReleaser_EditChangelog(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+
+	var edited string
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer abcd", r.Header.Get("Authorization"))
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /repos/kharf/myrepo/releases/tags/subdir%2Fv1.11.0":
+			w.Write([]byte(`{"id": 7, "tag_name": "subdir/v1.11.0", "body": "# What's Changed"}`))
+		case "PATCH /repos/kharf/myrepo/releases/7":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			edited = string(body)
+			w.Write([]byte(`{"id": 7}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	})
+	defer ts.Close()
+
+	tag := Tag{Name: "subdir/v1.11.0"}
+	changelog, err := releaser.Changelog(tag)
+	require.NoError(t, err)
+	assert.Equal(t, Changelog("# What's Changed"), changelog)
+
+	require.NoError(t, releaser.EditChangelog(tag, "# What's Changed\n- docs: newest"))
+	assert.Equal(t, `{"body":"# What's Changed\n- docs: newest"}`, edited)
+
+	_, err = releaser.Changelog(Tag{Name: "v3.0.0"})
+	assert.ErrorIs(t, err, ErrReleaseNotFound)
+}
+
+// changelogEditor is a ReleaseEditor keeping the Changelogs of the releases by Tag name.
+type changelogEditor map[string]Changelog
+
+func (editor changelogEditor) Changelog(tag Tag) (Changelog, error) {
+	changelog, found := editor[tag.Name]
+	if !found {
+		return "", ErrReleaseNotFound
+	}
+	return changelog, nil
+}
+
+func (editor changelogEditor) EditChangelog(tag Tag, changelog Changelog) error {
+	editor[tag.Name] = changelog
+	return nil
+}
+
+func TestEditChangelogs(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		releases, err := ListReleases(repository, Module{Path: ""}, PastReleaseOptions{})
+		require.NoError(t, err)
+		releases = releases[:3]
+
+		upToDate, err := releases[1].Changelog()
+		require.NoError(t, err)
+		editor := changelogEditor{"v1.10.0": "stale", "v1.9.0": upToDate}
+
+		edits, err := EditChangelogs(editor, releases, EditOptions{DryRun: true})
+		require.NoError(t, err)
+		require.Len(t, edits, 3)
+		assert.True(t, edits[0].Changed())
+		assert.False(t, edits[1].Changed())
+		assert.True(t, edits[2].Missing)
+		assert.Equal(t, Changelog("stale"), editor["v1.10.0"])
+
+		diff, err := edits[0].Diff()
+		require.NoError(t, err)
+		assert.Contains(t, diff, "--- v1.10.0 (current)\n+++ v1.10.0 (regenerated)\n")
+		assert.Contains(t, diff, "\n-stale\n+# What's Changed\n")
+
+		edits, err = EditChangelogs(editor, releases, EditOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, edits[0].Changelog, editor["v1.10.0"])
+		assert.NotContains(t, editor, "v1.8.0")
+	})
+}
+
+func TestEditChangelogs_LostEntries(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		releases, err := ListReleases(repository, Module{Path: ""}, PastReleaseOptions{})
+		require.NoError(t, err)
+		releases = releases[:1]
+
+		changelog, err := GenerateChangelog(append(Extract(releases[0].Commits), Change{Message: "bumped dependency libs/auth to v1.4.0", Semantic: Patch}))
+		require.NoError(t, err)
+		editor := changelogEditor{"v1.10.0": changelog}
+
+		edits, err := EditChangelogs(editor, releases, EditOptions{})
+		require.NoError(t, err)
+		require.Len(t, edits, 1)
+		assert.True(t, edits[0].Changed())
+		assert.Equal(t, []string{"bumped dependency libs/auth to v1.4.0"}, edits[0].Lost)
+		// the bumped dependency is kept
+		assert.Equal(t, changelog, editor["v1.10.0"])
+	})
+}
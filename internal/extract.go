@@ -1,6 +1,9 @@
 package monoreleaser
 
-import "strings"
+import (
+	"bufio"
+	"strings"
+)
 
 type Semantic string
 type Type string
@@ -10,10 +13,13 @@ const (
 	Major   Semantic = "major"
 	Minor   Semantic = "minor"
 	Patch   Semantic = "patch"
+	Revert  Semantic = "revert"
 
 	CommitSeperator        = ":"
 	ScopeStart             = "("
 	BreakingIndicator      = "!"
+	RevertPrefix           = "Revert \""
+	RevertBodyPrefix       = "This reverts commit "
 	UnknownType       Type = "unknown"
 	Fix               Type = "fix"
 	Feature           Type = "feat"
@@ -25,6 +31,7 @@ const (
 	Refactor          Type = "refactor"
 	Perf              Type = "perf"
 	Test              Type = "test"
+	RevertType        Type = "revert"
 )
 
 // Change is an interpreted Commit.
@@ -32,18 +39,30 @@ type Change struct {
 	Message  string
 	Semantic Semantic
 	Hash     string
+	// Hash of the Commit this Change reverts, if it is a revert and the hash is mentioned in the message.
+	Reverts string
 }
 
 // Inspects a list of Commits and transform each of them into Changes by extracting the semantic of the commit message.
+// Reverts and the Commits they revert cancel each other out when both are part of the list.
+// Reverts of Commits outside of the list are kept with the Revert Semantic.
 func Extract(commits []*Commit) []Change {
 	changes := make([]Change, 0, len(commits))
 	for _, commit := range commits {
-		changes = append(changes, Change{Message: commit.Message, Hash: commit.Hash, Semantic: extractSemantic(commit.Message)})
+		change := Change{Message: commit.Message, Hash: commit.Hash, Semantic: extractSemantic(commit.Message)}
+		if change.Semantic == Revert {
+			change.Reverts = extractRevertedHash(commit.Message)
+		}
+		changes = append(changes, change)
 	}
-	return changes
+	return cancelReverts(changes)
 }
 
 func extractSemantic(message string) Semantic {
+	if isRevert(message) {
+		return Revert
+	}
+
 	typeAndScope, _, found := strings.Cut(message, CommitSeperator)
 	if !found {
 		return Unknown
@@ -67,3 +86,86 @@ func extractSemantic(message string) Semantic {
 		return Unknown
 	}
 }
+
+func isRevert(message string) bool {
+	subject := subjectOf(message)
+	if strings.HasPrefix(subject, RevertPrefix) {
+		return true
+	}
+
+	typeAndScope, _, found := strings.Cut(subject, CommitSeperator)
+	if !found {
+		return false
+	}
+	typeStr, _, _ := strings.Cut(typeAndScope, ScopeStart)
+	return Type(typeStr) == RevertType
+}
+
+// extractRevertedHash looks for the "This reverts commit <hash>." line git adds to revert commits.
+func extractRevertedHash(message string) string {
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		hash, found := strings.CutPrefix(line, RevertBodyPrefix)
+		if !found {
+			continue
+		}
+		hash, _, _ = strings.Cut(hash, " ")
+		hash = strings.TrimRight(hash, ".,")
+		if hash != "" {
+			return hash
+		}
+	}
+	return ""
+}
+
+// revertedSubject returns the subject of the reverted Commit for messages like `Revert "feat: X"`.
+func revertedSubject(message string) string {
+	subject, found := strings.CutPrefix(subjectOf(message), RevertPrefix)
+	if !found {
+		return ""
+	}
+	return strings.TrimSuffix(subject, "\"")
+}
+
+func subjectOf(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(subject)
+}
+
+// cancelReverts removes reverts together with the Changes they revert.
+// Changes are expected to be ordered from newest to oldest, so that a revert of a revert is resolved first.
+func cancelReverts(changes []Change) []Change {
+	cancelled := make([]bool, len(changes))
+	for i, change := range changes {
+		if change.Semantic != Revert || cancelled[i] {
+			continue
+		}
+
+		for j := i + 1; j < len(changes); j++ {
+			if cancelled[j] || !reverts(change, changes[j]) {
+				continue
+			}
+			cancelled[i] = true
+			cancelled[j] = true
+			break
+		}
+	}
+
+	remaining := make([]Change, 0, len(changes))
+	for i, change := range changes {
+		if !cancelled[i] {
+			remaining = append(remaining, change)
+		}
+	}
+	return remaining
+}
+
+func reverts(revert Change, change Change) bool {
+	if revert.Reverts != "" {
+		return len(revert.Reverts) >= 7 && strings.HasPrefix(change.Hash, revert.Reverts)
+	}
+
+	subject := revertedSubject(revert.Message)
+	return subject != "" && subject == subjectOf(change.Message)
+}
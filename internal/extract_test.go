@@ -41,4 +41,65 @@ func TestExtract(t *testing.T) {
 			continue
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestExtract_RevertInRange(t *testing.T) {
+	commits := []*Commit{
+		{Hash: "3333333333", Message: "Revert \"feat: X\"\n\nThis reverts commit 1111111111."},
+		{Hash: "2222222222", Message: "fix: Y"},
+		{Hash: "1111111111", Message: "feat: X"},
+	}
+
+	changes := Extract(commits)
+
+	assert.Equal(t, []Change{{Hash: "2222222222", Message: "fix: Y", Semantic: Patch}}, changes)
+}
+
+func TestExtract_RevertInRange_AbbreviatedHash(t *testing.T) {
+	commits := []*Commit{
+		{Hash: "3333333333", Message: "revert: feat: X\n\nThis reverts commit 1111111."},
+		{Hash: "1111111111", Message: "feat: X"},
+	}
+
+	changes := Extract(commits)
+
+	assert.Len(t, changes, 0)
+}
+
+func TestExtract_RevertInRange_SubjectOnly(t *testing.T) {
+	commits := []*Commit{
+		{Hash: "3333333333", Message: "Revert \"feat: X\""},
+		{Hash: "1111111111", Message: "feat: X"},
+	}
+
+	changes := Extract(commits)
+
+	assert.Len(t, changes, 0)
+}
+
+func TestExtract_RevertOfRevert(t *testing.T) {
+	commits := []*Commit{
+		{Hash: "3333333333", Message: "Revert \"Revert \"feat: X\"\"\n\nThis reverts commit 2222222222."},
+		{Hash: "2222222222", Message: "Revert \"feat: X\"\n\nThis reverts commit 1111111111."},
+		{Hash: "1111111111", Message: "feat: X"},
+	}
+
+	changes := Extract(commits)
+
+	assert.Equal(t, []Change{{Hash: "1111111111", Message: "feat: X", Semantic: Minor}}, changes)
+}
+
+func TestExtract_RevertOfReleasedCommit(t *testing.T) {
+	message := "Revert \"feat: X\"\n\nThis reverts commit 1111111111."
+	commits := []*Commit{
+		{Hash: "3333333333", Message: message},
+		{Hash: "2222222222", Message: "fix: Y"},
+	}
+
+	changes := Extract(commits)
+
+	assert.Equal(t, []Change{
+		{Hash: "3333333333", Message: message, Semantic: Revert, Reverts: "1111111111"},
+		{Hash: "2222222222", Message: "fix: Y", Semantic: Patch},
+	}, changes)
+}
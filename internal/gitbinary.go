@@ -0,0 +1,741 @@
+package monoreleaser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+var (
+	ErrGitCommandFailed = errors.New("git command failed")
+	ErrUnsupportedAuth  = errors.New("unsupported authentication method")
+)
+
+// A GitBinaryRepository is a Repository, which shells out to the system's git binary.
+// Unlike GoGitRepository, it honors the whole git configuration, e.g. safe.directory or partial clones.
+type GitBinaryRepository struct {
+	name       string
+	dir        string
+	binary     string
+	deepen     *DeepenOptions
+	tagFormats TagFormats
+}
+
+var _ Repository = GitBinaryRepository{}
+
+// NewGitBinaryRepository creates a Repository for the git working tree in dir, using the git binary found in the PATH.
+func NewGitBinaryRepository(name string, dir string) GitBinaryRepository {
+	return GitBinaryRepository{
+		name:   name,
+		dir:    dir,
+		binary: "git",
+	}
+}
+
+// WithTagFormats returns a copy of the repository, which names Tags and parses their names with the given formats.
+func (repo GitBinaryRepository) WithTagFormats(formats TagFormats) GitBinaryRepository {
+	repo.tagFormats = formats
+	return repo
+}
+
+// WithAutoDeepen returns a copy of the repository, which fetches missing history from the remote,
+// whenever a shallow boundary prevents reaching the previous Tag.
+// Only http basic and token authentication are passed to git, without an Auth option git's own credential configuration is used.
+func (repo GitBinaryRepository) WithAutoDeepen(opts DeepenOptions) GitBinaryRepository {
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+	repo.deepen = &opts
+	return repo
+}
+
+// git runs a git command inside of the repository and returns its standard output.
+func (repo GitBinaryRepository) git(args ...string) ([]byte, error) {
+	return repo.run(nil, nil, args...)
+}
+
+// run runs a git command inside of the repository with the given standard input and additional environment variables.
+func (repo GitBinaryRepository) run(stdin io.Reader, env []string, args ...string) ([]byte, error) {
+	cmd := exec.Command(repo.binary, args...)
+	cmd.Dir = repo.dir
+	cmd.Stdin = stdin
+	if len(env) != 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, &gitCommandError{
+			args:   args,
+			stderr: strings.TrimSpace(stderr.String()),
+			err:    err,
+		}
+	}
+
+	return output, nil
+}
+
+type gitCommandError struct {
+	args   []string
+	stderr string
+	err    error
+}
+
+func (err *gitCommandError) Error() string {
+	return fmt.Sprintf("%s: git %s: %s", ErrGitCommandFailed, strings.Join(err.args, " "), err.stderr)
+}
+
+func (err *gitCommandError) Is(target error) bool {
+	return target == ErrGitCommandFailed
+}
+
+func (err *gitCommandError) Unwrap() error {
+	return err.err
+}
+
+// exitCode returns the exit code of a failed git command or -1, if the command could not be run at all.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func (repo GitBinaryRepository) Name() string {
+	return repo.name
+}
+
+func (repo GitBinaryRepository) Head() (string, error) {
+	return repo.resolve("HEAD")
+}
+
+// resolve returns the Commit hash a revision points to.
+func (repo GitBinaryRepository) resolve(revision string) (string, error) {
+	output, err := repo.git("rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if exitCode(err) == 1 {
+		return "", fmt.Errorf("%w: %s", plumbing.ErrReferenceNotFound, revision)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// History streams the output of git log, so that large histories are not read into memory at once.
+func (repo GitBinaryRepository) History(opts HistoryOptions) (*GenericIter[*Commit], error) {
+	args, err := repo.logArgs(opts, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	log, err := repo.startLog(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var endOfHistory error
+	return &GenericIter[*Commit]{
+		NextFunc: func() (*Commit, error) {
+			if endOfHistory != nil {
+				return nil, endOfHistory
+			}
+
+			commit, err := log.next()
+			if errors.Is(err, io.EOF) {
+				// like GoGitRepository, the available Commits are returned before the shallow boundary is reported
+				endOfHistory = repo.endOfHistory(opts)
+				return nil, endOfHistory
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			return commit, nil
+		},
+		CloseFunc: log.kill,
+	}, nil
+}
+
+// log lists the history reachable from the Hash option, limited to the given amount of Commits if limit is not negative.
+func (repo GitBinaryRepository) log(opts HistoryOptions, limit int) ([]*Commit, error) {
+	args, err := repo.logArgs(opts, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := repo.git(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLog(output), nil
+}
+
+// logArgs returns the arguments of the git log command listing the history of log.
+func (repo GitBinaryRepository) logArgs(opts HistoryOptions, limit int) ([]string, error) {
+	from := "HEAD"
+	if opts.Hash != "" {
+		hash, err := parseHash(opts.Hash)
+		if err != nil {
+			return nil, err
+		}
+		from = hash.String()
+	}
+
+	// fail the same way for repositories without any commits
+	from, err := repo.resolve(from)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", "-z", "--format=%H%n%B"}
+	if limit >= 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+	args = append(args, from)
+	if pathspecs := opts.Paths.pathspecs(opts.Module); pathspecs != nil {
+		args = append(append(args, "--"), pathspecs...)
+	}
+	return args, nil
+}
+
+// parseLog parses the output of git log -z --format=%H%n%B.
+func parseLog(output []byte) []*Commit {
+	var commits []*Commit
+	for _, entry := range bytes.Split(output, []byte{0}) {
+		if len(entry) == 0 {
+			continue
+		}
+		commits = append(commits, parseLogEntry(entry))
+	}
+	return commits
+}
+
+func parseLogEntry(entry []byte) *Commit {
+	hash, message, _ := strings.Cut(string(entry), "\n")
+	return &Commit{Hash: hash, Message: message}
+}
+
+// gitLog is a running git log -z --format=%H%n%B, whose Commits are read one by one.
+type gitLog struct {
+	cmd    *exec.Cmd
+	args   []string
+	stdout *bufio.Reader
+	stderr bytes.Buffer
+	done   bool
+}
+
+func (repo GitBinaryRepository) startLog(args []string) (*gitLog, error) {
+	log := &gitLog{cmd: exec.Command(repo.binary, args...), args: args}
+	log.cmd.Dir = repo.dir
+	log.cmd.Stderr = &log.stderr
+
+	stdout, err := log.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	log.stdout = bufio.NewReader(stdout)
+
+	if err := log.cmd.Start(); err != nil {
+		return nil, &gitCommandError{args: args, err: err}
+	}
+
+	return log, nil
+}
+
+// next returns the next Commit or io.EOF, once git log finished successfully.
+func (log *gitLog) next() (*Commit, error) {
+	for !log.done {
+		entry, err := log.stdout.ReadBytes(0)
+		if errors.Is(err, io.EOF) {
+			log.done = true
+			if err := log.cmd.Wait(); err != nil {
+				return nil, &gitCommandError{args: log.args, stderr: strings.TrimSpace(log.stderr.String()), err: err}
+			}
+		} else if err != nil {
+			return nil, err
+		}
+
+		entry = bytes.TrimSuffix(entry, []byte{0})
+		if len(entry) != 0 {
+			return parseLogEntry(entry), nil
+		}
+	}
+	return nil, io.EOF
+}
+
+// kill stops git log, so that a History, which is not read to its end, does not leave the git process behind.
+func (log *gitLog) kill() {
+	if !log.done {
+		log.done = true
+		log.cmd.Process.Kill()
+		log.cmd.Wait()
+	}
+}
+
+// endOfHistory returns the error marking the end of a History.
+// That is a ShallowHistoryError, if the History reaches a shallow boundary, because git silently stops there.
+// Its depth counts all available Commits, not only those passing the path filter.
+func (repo GitBinaryRepository) endOfHistory(opts HistoryOptions) error {
+	boundaries, err := repo.shallowBoundaries()
+	if err != nil {
+		return err
+	}
+
+	from := "HEAD"
+	if opts.Hash != "" {
+		from = opts.Hash
+	}
+
+	for boundary := range boundaries {
+		_, err := repo.git("merge-base", "--is-ancestor", boundary, from)
+		if err == nil {
+			output, err := repo.git("rev-list", "--count", from)
+			if err != nil {
+				return err
+			}
+			depth, err := strconv.Atoi(strings.TrimSpace(string(output)))
+			if err != nil {
+				return err
+			}
+			return &ShallowHistoryError{Depth: depth}
+		}
+		if exitCode(err) != 1 {
+			return err
+		}
+	}
+
+	return ErrEndOfHistory
+}
+
+// shallowBoundaries returns the Commits whose parents were not fetched by a shallow clone.
+func (repo GitBinaryRepository) shallowBoundaries() (map[string]bool, error) {
+	output, err := repo.git("rev-parse", "--git-path", "shallow")
+	if err != nil {
+		return nil, err
+	}
+
+	path := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repo.dir, path)
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	boundaries := make(map[string]bool)
+	for _, hash := range strings.Fields(string(content)) {
+		boundaries[hash] = true
+	}
+	return boundaries, nil
+}
+
+func (repo GitBinaryRepository) Commit(message string, opts CommitOptions) (*Commit, error) {
+	if _, err := repo.git(append([]string{"add", "--"}, opts.Paths...)...); err != nil {
+		return nil, err
+	}
+
+	// only the given files are committed, even if others are staged
+	if _, err := repo.git(append([]string{"commit", "--quiet", "--message", message, "--"}, opts.Paths...)...); err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Commit{
+		Hash:    hash,
+		Message: message,
+	}, nil
+}
+
+func (repo GitBinaryRepository) ResolveRevision(revision string) (string, error) {
+	return repo.resolve(revision)
+}
+
+func (repo GitBinaryRepository) CommitDate(revision string) (time.Time, error) {
+	hash, err := repo.resolve(revision)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	output, err := repo.git("show", "--no-patch", "--format=%cI", hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+}
+
+func (repo GitBinaryRepository) ReadFile(revision string, file string) ([]byte, error) {
+	hash, err := repo.resolve(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	object := hash + ":" + file
+	if _, err := repo.git("cat-file", "-e", object); err != nil {
+		if exitCode(err) == 128 {
+			return nil, ErrFileNotFound
+		}
+		return nil, err
+	}
+
+	return repo.git("cat-file", "blob", object)
+}
+
+func (repo GitBinaryRepository) CommitFiles(branch string, message string, files map[string][]byte) (*Commit, error) {
+	// a separate index keeps the working tree and its staged changes untouched
+	indexDir, err := os.MkdirTemp("", "monoreleaser-index")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(indexDir)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(indexDir, "index")}
+
+	branchRef := "refs/heads/" + branch
+	parent, err := repo.resolve(branchRef)
+	// the local branch must still not exist, when it is created on top of the remote one
+	oldValue := parent
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		parent, err = repo.resolve("refs/remotes/origin/" + branch)
+	}
+	switch {
+	case errors.Is(err, plumbing.ErrReferenceNotFound):
+		parent = ""
+	case err != nil:
+		return nil, err
+	default:
+		if _, err := repo.run(nil, env, "read-tree", parent); err != nil {
+			return nil, err
+		}
+	}
+
+	for file, content := range files {
+		output, err := repo.run(bytes.NewReader(content), nil, "hash-object", "-w", "--stdin")
+		if err != nil {
+			return nil, err
+		}
+		cacheInfo := "100644," + strings.TrimSpace(string(output)) + "," + file
+		if _, err := repo.run(nil, env, "update-index", "--add", "--cacheinfo", cacheInfo); err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := repo.run(nil, env, "write-tree")
+	if err != nil {
+		return nil, err
+	}
+
+	commitArgs := []string{"commit-tree", strings.TrimSpace(string(output)), "-m", message}
+	if parent != "" {
+		commitArgs = append(commitArgs, "-p", parent)
+	}
+	output, err = repo.git(commitArgs...)
+	if err != nil {
+		return nil, err
+	}
+	hash := strings.TrimSpace(string(output))
+
+	// fails, if the branch has been moved in the meantime
+	if _, err := repo.git("update-ref", branchRef, hash, oldValue); err != nil {
+		return nil, err
+	}
+
+	return &Commit{
+		Hash:    hash,
+		Message: message,
+	}, nil
+}
+
+func (repo GitBinaryRepository) Tag(version string, opts TagOptions) (*Tag, error) {
+	commits, err := repo.log(HistoryOptions{Hash: opts.Hash, Module: opts.Module, Paths: opts.Paths}, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commits) == 0 {
+		return nil, ErrEndOfHistory
+	}
+
+	name, err := repo.tagFormats.name(resolveTagModule(opts.Module, opts.TagModule), version)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repo.git("tag", "--", name, commits[0].Hash); err != nil {
+		return nil, err
+	}
+
+	return &Tag{
+		Name:    name,
+		Hash:    commits[0].Hash,
+		Version: version,
+	}, nil
+}
+
+func (repo GitBinaryRepository) GetTag(version string, opts GetTagOptions) (*Tag, error) {
+	name, err := repo.tagFormats.name(opts.Module, version)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := repo.git("rev-parse", "--verify", "--quiet", "refs/tags/"+name)
+	if exitCode(err) == 1 {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tag{
+		Name:    name,
+		Hash:    strings.TrimSpace(string(output)),
+		Version: version,
+	}, nil
+}
+
+func (repo GitBinaryRepository) GetTags(opts GetTagOptions) ([]Tag, error) {
+	output, err := repo.git("for-each-ref", "--format=%(refname:strip=2) %(objectname)", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	var moduleTags []Tag
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		name, hash, found := strings.Cut(scanner.Text(), " ")
+		if !found {
+			continue
+		}
+		if tag, ok := repo.tagFormats.moduleTag(opts.Module, name, hash); ok {
+			moduleTags = append(moduleTags, tag)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sortTags(moduleTags)
+
+	return moduleTags, nil
+}
+
+// Diff returns the Commits reachable from newerTag, but not from olderTag, newest first.
+// It follows the same rules as GoGitRepository.Diff, so that both implementations return the same Commits in the same order.
+func (repo GitBinaryRepository) Diff(newerTag Tag, olderTag *Tag, opts DiffOptions) ([]*Commit, error) {
+	// a missing older Commit is reported with a depth of 0, which is still deepened once
+	previousDepth := -1
+	for {
+		commits, err := repo.diff(newerTag, olderTag, opts)
+
+		var shallowErr *ShallowHistoryError
+		if repo.deepen == nil || !errors.As(err, &shallowErr) {
+			return commits, err
+		}
+
+		// the remote did not provide any more history
+		if shallowErr.Depth <= previousDepth {
+			return []*Commit{}, err
+		}
+		previousDepth = shallowErr.Depth
+
+		if err := repo.deepenHistory(shallowErr); err != nil {
+			return []*Commit{}, err
+		}
+	}
+}
+
+func (repo GitBinaryRepository) diff(newerTag Tag, olderTag *Tag, opts DiffOptions) ([]*Commit, error) {
+	newHash, err := parseHash(newerTag.Hash)
+	if err != nil {
+		return []*Commit{}, err
+	}
+
+	boundaries, err := repo.shallowBoundaries()
+	if err != nil {
+		return []*Commit{}, err
+	}
+
+	revisions := []string{newHash.String()}
+	var since *time.Time
+	// the older Commit itself might not have been fetched yet
+	oldMissing := false
+	if olderTag != nil {
+		oldHash, err := parseHash(olderTag.Hash)
+		if err != nil {
+			return []*Commit{}, err
+		}
+
+		since, err = repo.commitTime(oldHash.String())
+		switch {
+		case len(boundaries) != 0 && exitCode(err) == 128:
+			oldMissing = true
+		case err != nil:
+			return []*Commit{}, err
+		default:
+			revisions = append(revisions, "^"+oldHash.String())
+		}
+	}
+
+	var firstParent []string
+	if opts.FirstParent {
+		firstParent = []string{"--first-parent"}
+	}
+
+	rangeArgs := append(append([]string{"rev-list", "--parents"}, firstParent...), revisions...)
+	output, err := repo.git(rangeArgs...)
+	if err != nil {
+		return []*Commit{}, err
+	}
+
+	parents := make(map[string][]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 0 {
+			parents[fields[0]] = fields[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return []*Commit{}, err
+	}
+
+	ordered := childrenFirst(newHash.String(), func(hash string) ([]string, bool) {
+		commitParents, ok := parents[hash]
+		return commitParents, ok
+	}, opts.FirstParent)
+
+	if depth := shallowDepth(ordered, parents, boundaries); depth > 0 || oldMissing {
+		return []*Commit{}, &ShallowHistoryError{Depth: depth, Since: since}
+	}
+
+	// without history simplification, git lists merges only if they differ from all of their parents, just like GoGitRepository
+	logArgs := append(append([]string{"log", "-z", "--format=%H%n%B", "--full-history"}, firstParent...), revisions...)
+	if pathspecs := opts.Paths.pathspecs(opts.Module); pathspecs != nil {
+		logArgs = append(append(logArgs, "--"), pathspecs...)
+	}
+	output, err = repo.git(logArgs...)
+	if err != nil {
+		return []*Commit{}, err
+	}
+
+	messages := make(map[string]*Commit)
+	for _, commit := range parseLog(output) {
+		messages[commit.Hash] = commit
+	}
+
+	commitDiffs := make([]*Commit, 0, len(messages))
+	for _, hash := range ordered {
+		if commit, ok := messages[hash]; ok {
+			commitDiffs = append(commitDiffs, commit)
+		}
+	}
+
+	return commitDiffs, nil
+}
+
+func (repo GitBinaryRepository) commitTime(hash string) (*time.Time, error) {
+	output, err := repo.git("log", "-1", "--format=%ct", hash)
+	if err != nil {
+		return nil, err
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	commitTime := time.Unix(seconds, 0)
+	return &commitTime, nil
+}
+
+// shallowDepth returns the depth of the deepest shallow boundary inside of an ordered range, or 0 if the range is complete.
+func shallowDepth(ordered []string, parents map[string][]string, boundaries map[string]bool) int {
+	if len(boundaries) == 0 || len(ordered) == 0 {
+		return 0
+	}
+
+	depths := make(map[string]int, len(ordered))
+	depths[ordered[0]] = 1
+	depth := 0
+	for _, hash := range ordered {
+		for _, parent := range parents[hash] {
+			if parentDepth, ok := depths[parent]; !ok || parentDepth > depths[hash]+1 {
+				depths[parent] = depths[hash] + 1
+			}
+		}
+
+		if boundaries[hash] && depths[hash] > depth {
+			depth = depths[hash]
+		}
+	}
+
+	return depth
+}
+
+// deepenHistory fetches more history from the remote than is available locally.
+// It returns the original error, if the configured maximum depth is already reached.
+func (repo GitBinaryRepository) deepenHistory(shallowErr *ShallowHistoryError) error {
+	if repo.deepen.MaxDepth != 0 && shallowErr.Depth >= repo.deepen.MaxDepth {
+		return shallowErr
+	}
+
+	by := deepenBy(shallowErr.Depth)
+	if repo.deepen.MaxDepth != 0 && shallowErr.Depth+by > repo.deepen.MaxDepth {
+		by = repo.deepen.MaxDepth - shallowErr.Depth
+	}
+
+	env, err := authEnv(repo.deepen.Auth)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.run(nil, env, "fetch", "--deepen="+strconv.Itoa(by), repo.deepen.Remote)
+	return err
+}
+
+// authEnv returns the environment variables, which configure git to send the Authorization header of the given Auth.
+// The header is passed by the environment, so that the credentials neither show up in the process list nor in the git config.
+func authEnv(auth transport.AuthMethod) ([]string, error) {
+	var header string
+	switch auth := auth.(type) {
+	case nil:
+		return nil, nil
+	case *githttp.BasicAuth:
+		header = "Basic " + base64.StdEncoding.EncodeToString([]byte(auth.Username+":"+auth.Password))
+	case *githttp.TokenAuth:
+		header = "Bearer " + auth.Token
+	default:
+		return nil, fmt.Errorf("%w: %s is not supported by the git binary", ErrUnsupportedAuth, auth.Name())
+	}
+
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: " + header,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	ErrNoGoModule         = errors.New("go.mod does not declare a module path")
+	ErrUnresolvableGoTag  = errors.New("go could never resolve the tag")
+	ErrNonCanonicalGoTag  = fmt.Errorf("%w: version is not a canonical semantic version like v1.2.3", ErrUnresolvableGoTag)
+	ErrGoMajorVersionPath = fmt.Errorf("%w: major version does not match the module path", ErrUnresolvableGoTag)
+	ErrGoModuleDirectory  = fmt.Errorf("%w: module path does not end with the module directory", ErrUnresolvableGoTag)
+)
+
+// A GoModule is a Go module declared by a go.mod file inside of a Module directory.
+type GoModule struct {
+	// Path is the module path declared in the go.mod file, e.g. github.com/kharf/monoreleaser/libs/auth/v2.
+	Path string
+	// Dir is the Module directory containing the go.mod file.
+	Dir string
+}
+
+// ReadGoModule reads the go.mod file of a Module.
+// Root is the directory of the repository. If it is empty, the current directory will be used.
+func ReadGoModule(fsys afero.Fs, root string, dir string) (*GoModule, error) {
+	if root == "" {
+		root = "."
+	}
+
+	goMod, err := readGoMod(fsys, root, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if goMod.Module == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoGoModule, ModuleName(dir))
+	}
+
+	return &GoModule{Path: goMod.Module.Mod.Path, Dir: dir}, nil
+}
+
+// TagModule returns the Module the Go module's Tags have to be prefixed with, in the form of TagOptions.TagModule.
+// It is empty for most modules, because the Tags are prefixed with the Module directory.
+// Modules in major version subdirectories like libs/auth/v2 share the Tags of their parent directory, e.g. libs/auth/v2.0.0.
+func (mod GoModule) TagModule() string {
+	_, pathMajor, ok := module.SplitPathVersion(mod.Path)
+	if !ok || !strings.HasPrefix(pathMajor, "/") {
+		return ""
+	}
+
+	majorDir := pathMajor[1:]
+	if mod.Dir == majorDir {
+		return RootModule
+	}
+
+	tagModule, found := strings.CutSuffix(mod.Dir, "/"+majorDir)
+	if !found {
+		return ""
+	}
+	return tagModule
+}
+
+// ValidateTag checks whether go get is able to resolve the version of the Go module, once it is tagged.
+// Versions need to be canonical, v2+ versions require a matching /vN module path suffix
+// and the module path has to end with the directory the Tags are prefixed with.
+func (mod GoModule) ValidateTag(version string) error {
+	if !semver.IsValid(version) || semver.Canonical(version) != version {
+		return fmt.Errorf("%w: %s", ErrNonCanonicalGoTag, version)
+	}
+
+	pathPrefix, pathMajor, ok := module.SplitPathVersion(mod.Path)
+	if !ok {
+		return fmt.Errorf("%w: invalid module path %s", ErrUnresolvableGoTag, mod.Path)
+	}
+
+	if err := module.CheckPathMajor(version, pathMajor); err != nil {
+		return fmt.Errorf("%w: %s", ErrGoMajorVersionPath, err)
+	}
+
+	tagModule := resolveTagModule(mod.Dir, mod.TagModule())
+	if tagModule != "" && !strings.HasSuffix(pathPrefix, "/"+tagModule) {
+		return fmt.Errorf("%w: %s is not in %s", ErrGoModuleDirectory, mod.Path, tagModule)
+	}
+
+	return nil
+}
+
+// ValidateGoRelease validates the version of the Go module inside of a Module directory, if there is one,
+// and returns the Module its Tags have to be prefixed with, in the form of TagOptions.TagModule.
+// Root is the directory of the repository. If it is empty, the current directory will be used.
+func ValidateGoRelease(fsys afero.Fs, root string, dir string, version string) (string, error) {
+	if root == "" {
+		root = "."
+	}
+
+	exists, err := afero.Exists(fsys, filepath.Join(root, dir, "go.mod"))
+	if err != nil || !exists {
+		return "", err
+	}
+
+	goModule, err := ReadGoModule(fsys, root, dir)
+	if errors.Is(err, ErrNoGoModule) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := goModule.ValidateTag(version); err != nil {
+		return "", fmt.Errorf("%s: %w", ModuleName(dir), err)
+	}
+
+	return goModule.TagModule(), nil
+}
@@ -0,0 +1,91 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoModule_ValidateTag(t *testing.T) {
+	testCases := []struct {
+		name      string
+		path      string
+		dir       string
+		version   string
+		tagModule string
+		err       error
+	}{
+		{name: "root", path: "github.com/acme/repo", dir: "", version: "v1.2.3"},
+		{name: "submodule", path: "github.com/acme/repo/libs/auth", dir: "libs/auth", version: "v0.1.0"},
+		{name: "prerelease", path: "github.com/acme/repo/libs/auth", dir: "libs/auth", version: "v1.0.0-rc.1"},
+		{name: "major version suffix", path: "github.com/acme/repo/libs/auth/v2", dir: "libs/auth", version: "v2.1.0"},
+		{
+			name:      "major version subdirectory",
+			path:      "github.com/acme/repo/libs/auth/v3",
+			dir:       "libs/auth/v3",
+			version:   "v3.0.0",
+			tagModule: "libs/auth",
+		},
+		{name: "root major version subdirectory", path: "github.com/acme/repo/v2", dir: "v2", version: "v2.0.0", tagModule: RootModule},
+		{name: "short version", path: "github.com/acme/repo", dir: "", version: "v1", err: ErrNonCanonicalGoTag},
+		{name: "missing prefix", path: "github.com/acme/repo", dir: "", version: "1.2.3", err: ErrNonCanonicalGoTag},
+		{name: "build metadata", path: "github.com/acme/repo", dir: "", version: "v1.2.3+build", err: ErrNonCanonicalGoTag},
+		{name: "missing major version suffix", path: "github.com/acme/repo/libs/auth", dir: "libs/auth", version: "v2.0.0", err: ErrGoMajorVersionPath},
+		{name: "wrong major version suffix", path: "github.com/acme/repo/libs/auth/v2", dir: "libs/auth", version: "v3.0.0", err: ErrGoMajorVersionPath},
+		{name: "unnecessary major version suffix", path: "github.com/acme/repo/libs/auth/v2", dir: "libs/auth", version: "v1.5.0", err: ErrGoMajorVersionPath},
+		{name: "directory mismatch", path: "github.com/acme/repo/auth", dir: "libs/auth", version: "v1.0.0", err: ErrGoModuleDirectory},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			goModule := GoModule{Path: testCase.path, Dir: testCase.dir}
+			err := goModule.ValidateTag(testCase.version)
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				assert.ErrorIs(t, err, ErrUnresolvableGoTag)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.tagModule, goModule.TagModule())
+		})
+	}
+}
+
+func TestValidateGoRelease(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "libs/auth/go.mod", []byte("module github.com/acme/repo/libs/auth\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "libs/auth/v2/go.mod", []byte("module github.com/acme/repo/libs/auth/v2\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "web/package.json", []byte("{}"), 0o644))
+
+	tagModule, err := ValidateGoRelease(fs, "", "libs/auth", "v1.4.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "", tagModule)
+
+	tagModule, err = ValidateGoRelease(fs, "", "libs/auth/v2", "v2.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "libs/auth", tagModule)
+
+	_, err = ValidateGoRelease(fs, "", "libs/auth", "v2.0.0")
+	assert.ErrorIs(t, err, ErrGoMajorVersionPath)
+	assert.ErrorContains(t, err, "libs/auth: ")
+
+	tagModule, err = ValidateGoRelease(fs, "", "web", "v2")
+	assert.NoError(t, err)
+	assert.Equal(t, "", tagModule)
+}
+
+func TestDiscoverModules_GoMajorVersionSubdirectory(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "libs/auth/go.mod", []byte("module github.com/acme/repo/libs/auth\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "libs/auth/v2/go.mod", []byte("module github.com/acme/repo/libs/auth/v2\n"), 0o644))
+
+	modules, err := DiscoverModules(fs, DiscoverOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Path: "libs/auth", Markers: []string{"go.mod"}},
+		{Path: "libs/auth/v2", Markers: []string{"go.mod"}, TagModule: "libs/auth"},
+	}, modules)
+}
@@ -0,0 +1,352 @@
+package monoreleaser
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/afero"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidChart = errors.New("invalid helm chart")
+
+// ChartMetadata is the content of a Chart.yaml, which is listed in the index of a chart repository as well.
+type ChartMetadata struct {
+	APIVersion  string `yaml:"apiVersion"`
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	AppVersion  string `yaml:"appVersion,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	// Other contains the remaining fields, e.g. dependencies or maintainers.
+	Other map[string]any `yaml:",inline"`
+}
+
+// A ChartPackage is a packaged Helm chart, like helm package creates it.
+type ChartPackage struct {
+	Metadata ChartMetadata
+	// Content is the gzipped tar archive of the chart.
+	Content []byte
+}
+
+// FileName returns the name of the archive, e.g. api-1.2.3.tgz.
+func (pkg ChartPackage) FileName() string {
+	return pkg.Metadata.Name + "-" + pkg.Metadata.Version + ".tgz"
+}
+
+// Digest returns the sha256 checksum of the archive, which helm verifies.
+func (pkg ChartPackage) Digest() string {
+	sum := sha256.Sum256(pkg.Content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Artifact returns the archive to upload it alongside the changelog.
+func (pkg ChartPackage) Artifact() Artifact {
+	return Artifact{
+		Reader: bytes.NewReader(pkg.Content),
+		Name:   pkg.FileName(),
+		Size:   int64(len(pkg.Content)),
+	}
+}
+
+// ChartVersionFiles returns the VersionFiles of a Module, which include the Chart.yaml of a Helm chart.
+func ChartVersionFiles(module Module) []string {
+	if !module.Chart || contains(module.VersionFiles, "Chart.yaml") {
+		return module.VersionFiles
+	}
+	return append(append([]string{}, module.VersionFiles...), "Chart.yaml")
+}
+
+// PackageChart packages the chart inside of a directory without the helm binary.
+// Files matching the patterns of a .helmignore file are left out, negated patterns are not supported.
+func PackageChart(fsys afero.Fs, dir string) (*ChartPackage, error) {
+	chartYaml, err := afero.ReadFile(fsys, filepath.Join(dir, "Chart.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata ChartMetadata
+	if err := yaml.Unmarshal(chartYaml, &metadata); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidChart, err)
+	}
+	if metadata.Name == "" || metadata.Version == "" {
+		return nil, fmt.Errorf("%w: Chart.yaml needs a name and a version", ErrInvalidChart)
+	}
+
+	ignored, err := readHelmIgnore(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	gzipWriter := gzip.NewWriter(&content)
+	tarWriter := tar.NewWriter(gzipWriter)
+	err = afero.Walk(fsys, dir, func(file string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		fileContent, err := afero.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		if err := tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     path.Join(metadata.Name, rel),
+			Mode:     0o644,
+			Size:     int64(len(fileContent)),
+			ModTime:  info.ModTime(),
+		}); err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(fileContent)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return &ChartPackage{Metadata: metadata, Content: content.Bytes()}, nil
+}
+
+// readHelmIgnore returns whether a file or directory of the chart is ignored by its .helmignore file.
+func readHelmIgnore(fsys afero.Fs, dir string) (func(file string, isDir bool) bool, error) {
+	content, err := afero.ReadFile(fsys, filepath.Join(dir, ".helmignore"))
+	if errors.Is(err, os.ErrNotExist) {
+		content = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return func(file string, isDir bool) bool {
+		for _, pattern := range patterns {
+			dirOnly := strings.HasSuffix(pattern, "/")
+			if dirOnly && !isDir {
+				continue
+			}
+			pattern = strings.TrimSuffix(pattern, "/")
+
+			// patterns without a slash match the name of a file in any directory
+			name := file
+			if !strings.Contains(pattern, "/") {
+				name = path.Base(file)
+			}
+			if matched, _ := path.Match(strings.TrimPrefix(pattern, "/"), name); matched {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// A HelmRepository is a chart repository stored inside of the git repository, e.g. to be served by GitHub Pages.
+type HelmRepository struct {
+	// URL the chart repository is served from.
+	// If this option is not set, the charts are referenced relative to the index.
+	URL string
+	// Branch the chart repository is committed to without touching the working tree, e.g. gh-pages.
+	// If this option is not set, the chart repository is written to the working tree and committed to the current branch.
+	Branch string
+	// Dir is the directory of the chart repository inside of the Branch or the working tree.
+	// If this option is not set, the root directory will be used.
+	Dir string
+}
+
+// chartIndex is the index.yaml of a chart repository.
+type chartIndex struct {
+	APIVersion string                       `yaml:"apiVersion"`
+	Entries    map[string][]chartIndexEntry `yaml:"entries"`
+	Generated  time.Time                    `yaml:"generated"`
+}
+
+// chartIndexEntry lists a chart version with the fields of its Chart.yaml.
+// yaml.v3 does not support inlining ChartMetadata with its own inline map, so that the fields are repeated.
+type chartIndexEntry struct {
+	APIVersion  string         `yaml:"apiVersion"`
+	Name        string         `yaml:"name"`
+	Version     string         `yaml:"version"`
+	AppVersion  string         `yaml:"appVersion,omitempty"`
+	Description string         `yaml:"description,omitempty"`
+	URLs        []string       `yaml:"urls"`
+	Created     time.Time      `yaml:"created"`
+	Digest      string         `yaml:"digest"`
+	Other       map[string]any `yaml:",inline"`
+}
+
+// add lists a chart in the index, replacing a previous package of the same version.
+func (index *chartIndex) add(pkg *ChartPackage, url string, now time.Time) {
+	if index.APIVersion == "" {
+		index.APIVersion = "v1"
+	}
+	if index.Entries == nil {
+		index.Entries = make(map[string][]chartIndexEntry)
+	}
+
+	name := pkg.Metadata.Name
+	entries := make([]chartIndexEntry, 0, len(index.Entries[name])+1)
+	for _, entry := range index.Entries[name] {
+		if entry.Version != pkg.Metadata.Version {
+			entries = append(entries, entry)
+		}
+	}
+	entries = append(entries, chartIndexEntry{
+		APIVersion:  pkg.Metadata.APIVersion,
+		Name:        name,
+		Version:     pkg.Metadata.Version,
+		AppVersion:  pkg.Metadata.AppVersion,
+		Description: pkg.Metadata.Description,
+		URLs:        []string{url},
+		Created:     now,
+		Digest:      pkg.Digest(),
+		Other:       pkg.Metadata.Other,
+	})
+	sort.SliceStable(entries, func(i, j int) bool {
+		return semver.Compare("v"+strings.TrimPrefix(entries[i].Version, "v"), "v"+strings.TrimPrefix(entries[j].Version, "v")) > 0
+	})
+
+	index.Entries[name] = entries
+	index.Generated = now
+}
+
+// Optional parameters for publishing a chart.
+type PublishChartOptions struct {
+	// Root is the directory of the repository.
+	// If this option is not set, the current directory will be used.
+	Root string
+	// Message of the Commit adding the chart to the chart repository.
+	// If this option is not set, the message will name the chart archive.
+	Message string
+}
+
+// PublishChart adds a packaged chart to a chart repository and updates its index.yaml, which is committed.
+func PublishChart(
+	fsys afero.Fs,
+	repository Repository,
+	helmRepository HelmRepository,
+	pkg *ChartPackage,
+	opts PublishChartOptions,
+) (*Commit, error) {
+	if opts.Root == "" {
+		opts.Root = "."
+	}
+	if opts.Message == "" {
+		opts.Message = "chore(helm): publish " + pkg.FileName()
+	}
+
+	indexPath := path.Join(helmRepository.Dir, "index.yaml")
+	packagePath := path.Join(helmRepository.Dir, pkg.FileName())
+
+	var content []byte
+	var err error
+	if helmRepository.Branch != "" {
+		content, err = repository.ReadFile("refs/heads/"+helmRepository.Branch, indexPath)
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// like CommitFiles, a branch which has only been fetched yet is continued
+			content, err = repository.ReadFile("refs/remotes/origin/"+helmRepository.Branch, indexPath)
+		}
+		if errors.Is(err, ErrFileNotFound) || errors.Is(err, plumbing.ErrReferenceNotFound) {
+			err = nil
+		}
+	} else {
+		content, err = afero.ReadFile(fsys, filepath.Join(opts.Root, filepath.FromSlash(indexPath)))
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var index chartIndex
+	if err := yaml.Unmarshal(content, &index); err != nil {
+		return nil, err
+	}
+
+	url := pkg.FileName()
+	if helmRepository.URL != "" {
+		url = strings.TrimSuffix(helmRepository.URL, "/") + "/" + url
+	}
+	index.add(pkg, url, time.Now().UTC())
+
+	var indexContent bytes.Buffer
+	encoder := yaml.NewEncoder(&indexContent)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&index); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	if helmRepository.Branch != "" {
+		return repository.CommitFiles(helmRepository.Branch, opts.Message, map[string][]byte{
+			indexPath:   indexContent.Bytes(),
+			packagePath: pkg.Content,
+		})
+	}
+
+	if err := fsys.MkdirAll(filepath.Join(opts.Root, filepath.FromSlash(helmRepository.Dir)), 0o755); err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{indexPath: indexContent.Bytes(), packagePath: pkg.Content}
+	for _, file := range []string{indexPath, packagePath} {
+		if err := afero.WriteFile(fsys, filepath.Join(opts.Root, filepath.FromSlash(file)), files[file], 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return repository.Commit(opts.Message, CommitOptions{Paths: []string{indexPath, packagePath}})
+}
@@ -0,0 +1,231 @@
+package monoreleaser
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testChartYaml = `apiVersion: v2
+name: api
+version: 1.2.0
+appVersion: "1.2.0"
+maintainers:
+  - name: orca
+`
+
+func writeTestChart(t *testing.T, fs afero.Fs, dir string) {
+	files := map[string]string{
+		"Chart.yaml":                testChartYaml,
+		"values.yaml":               "replicas: 1\n",
+		"templates/deployment.yaml": "kind: Deployment\n",
+		"templates/NOTES.txt":       "notes\n",
+		"ci/values.yaml":            "replicas: 2\n",
+		".helmignore":               "# comment\nci/\n*.txt\n",
+	}
+	for file, content := range files {
+		require.NoError(t, fs.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0o755))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, file), []byte(content), 0o644))
+	}
+}
+
+func chartFiles(t *testing.T, pkg *ChartPackage) map[string]string {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(pkg.Content))
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+
+	files := make(map[string]string)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+}
+
+func TestPackageChart(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestChart(t, fs, "charts/api")
+
+	pkg, err := PackageChart(fs, "charts/api")
+	require.NoError(t, err)
+	assert.Equal(t, "api-1.2.0.tgz", pkg.FileName())
+	assert.Equal(t, "1.2.0", pkg.Metadata.AppVersion)
+	assert.Len(t, pkg.Digest(), 64)
+	assert.Equal(t, map[string]string{
+		"api/Chart.yaml":                testChartYaml,
+		"api/values.yaml":               "replicas: 1\n",
+		"api/templates/deployment.yaml": "kind: Deployment\n",
+		"api/.helmignore":               "# comment\nci/\n*.txt\n",
+	}, chartFiles(t, pkg))
+
+	artifact := pkg.Artifact()
+	assert.Equal(t, "api-1.2.0.tgz", artifact.Name)
+	assert.Equal(t, int64(len(pkg.Content)), artifact.Size)
+}
+
+func TestPackageChart_Invalid(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "chart/Chart.yaml", []byte("name: api\n"), 0o644))
+
+	_, err := PackageChart(fs, "chart")
+	assert.ErrorIs(t, err, ErrInvalidChart)
+
+	_, err = PackageChart(fs, "missing")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestChartVersionFiles(t *testing.T) {
+	assert.Equal(t, []string{"VERSION", "Chart.yaml"}, ChartVersionFiles(Module{Chart: true, VersionFiles: []string{"VERSION"}}))
+	assert.Equal(t, []string{"Chart.yaml"}, ChartVersionFiles(Module{Chart: true, VersionFiles: []string{"Chart.yaml"}}))
+	assert.Equal(t, []string{"VERSION"}, ChartVersionFiles(Module{VersionFiles: []string{"VERSION"}}))
+}
+
+func TestChartIndex_Add(t *testing.T) {
+	index := chartIndex{}
+	for _, version := range []string{"1.9.0", "1.10.0", "1.10.0-rc.1", "1.9.0"} {
+		pkg := &ChartPackage{Metadata: ChartMetadata{Name: "api", Version: version}, Content: []byte(version)}
+		index.add(pkg, "https://charts.example.com/"+pkg.FileName(), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	}
+
+	assert.Equal(t, "v1", index.APIVersion)
+	var versions []string
+	for _, entry := range index.Entries["api"] {
+		versions = append(versions, entry.Version)
+	}
+	assert.Equal(t, []string{"1.10.0", "1.10.0-rc.1", "1.9.0"}, versions)
+	assert.Equal(t, []string{"https://charts.example.com/api-1.10.0.tgz"}, index.Entries["api"][0].URLs)
+}
+
+func readIndex(t *testing.T, content []byte) chartIndex {
+	var index chartIndex
+	require.NoError(t, yaml.Unmarshal(content, &index))
+	return index
+}
+
+func TestPublishChart_Branch(t *testing.T) {
+	var dir string
+	forEachImplementation(t, func(t *testing.T) string {
+		dir = versionFileRepo(t)
+		return dir
+	}, func(t *testing.T, repository Repository) {
+		fs := afero.NewMemMapFs()
+		writeTestChart(t, fs, "api")
+		pkg, err := PackageChart(fs, "api")
+		require.NoError(t, err)
+
+		helmRepository := HelmRepository{URL: "https://orca.github.io/charts/", Branch: "gh-pages", Dir: "charts"}
+		commit, err := PublishChart(fs, repository, helmRepository, pkg, PublishChartOptions{Root: dir})
+		require.NoError(t, err)
+		assert.Equal(t, "chore(helm): publish api-1.2.0.tgz", commit.Message)
+
+		require.NoError(t, afero.WriteFile(fs, "api/Chart.yaml", []byte("name: api\nversion: 1.3.0\n"), 0o644))
+		next, err := PackageChart(fs, "api")
+		require.NoError(t, err)
+		_, err = PublishChart(fs, repository, helmRepository, next, PublishChartOptions{Root: dir, Message: "publish"})
+		require.NoError(t, err)
+
+		archive, err := repository.ReadFile("gh-pages", "charts/api-1.2.0.tgz")
+		require.NoError(t, err)
+		assert.Equal(t, pkg.Content, archive)
+
+		content, err := repository.ReadFile("gh-pages", "charts/index.yaml")
+		require.NoError(t, err)
+		index := readIndex(t, content)
+		require.Len(t, index.Entries["api"], 2)
+		latest := index.Entries["api"][0]
+		assert.Equal(t, "1.3.0", latest.Version)
+		assert.Equal(t, []string{"https://orca.github.io/charts/api-1.3.0.tgz"}, latest.URLs)
+		assert.Equal(t, next.Digest(), latest.Digest)
+		// unknown fields of the Chart.yaml are kept
+		assert.Equal(t, []any{map[string]any{"name": "orca"}}, index.Entries["api"][1].Other["maintainers"])
+	})
+}
+
+func TestPublishChart_RemoteBranch(t *testing.T) {
+	var dir string
+	forEachImplementation(t, func(t *testing.T) string {
+		dir = versionFileRepo(t)
+		return dir
+	}, func(t *testing.T, repository Repository) {
+		fs := afero.NewMemMapFs()
+		writeTestChart(t, fs, "api")
+		pkg, err := PackageChart(fs, "api")
+		require.NoError(t, err)
+
+		helmRepository := HelmRepository{Branch: "gh-pages"}
+		published, err := PublishChart(fs, repository, helmRepository, pkg, PublishChartOptions{Root: dir})
+		require.NoError(t, err)
+
+		// like a fresh clone, which has only fetched the branch
+		gitRepository, err := git.PlainOpen(dir)
+		require.NoError(t, err)
+		remoteRef := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "gh-pages"), plumbing.NewHash(published.Hash))
+		require.NoError(t, gitRepository.Storer.SetReference(remoteRef))
+		require.NoError(t, gitRepository.Storer.RemoveReference(plumbing.NewBranchReferenceName("gh-pages")))
+
+		require.NoError(t, afero.WriteFile(fs, "api/Chart.yaml", []byte("name: api\nversion: 1.3.0\n"), 0o644))
+		next, err := PackageChart(fs, "api")
+		require.NoError(t, err)
+		_, err = PublishChart(fs, repository, helmRepository, next, PublishChartOptions{Root: dir})
+		require.NoError(t, err)
+
+		parent, err := repository.ResolveRevision("gh-pages~1")
+		require.NoError(t, err)
+		assert.Equal(t, published.Hash, parent)
+
+		content, err := repository.ReadFile("gh-pages", "index.yaml")
+		require.NoError(t, err)
+		index := readIndex(t, content)
+		require.Len(t, index.Entries["api"], 2)
+		_, err = repository.ReadFile("gh-pages", "api-1.2.0.tgz")
+		assert.NoError(t, err)
+	})
+}
+
+func TestPublishChart_Dir(t *testing.T) {
+	var dir string
+	forEachImplementation(t, func(t *testing.T) string {
+		dir = versionFileRepo(t)
+		return dir
+	}, func(t *testing.T, repository Repository) {
+		fs := afero.NewOsFs()
+		writeTestChart(t, fs, filepath.Join(dir, "api"))
+		pkg, err := PackageChart(fs, filepath.Join(dir, "api"))
+		require.NoError(t, err)
+
+		commit, err := PublishChart(fs, repository, HelmRepository{Dir: "docs"}, pkg, PublishChartOptions{Root: dir})
+		require.NoError(t, err)
+
+		head, err := repository.Head()
+		require.NoError(t, err)
+		assert.Equal(t, head, commit.Hash)
+
+		content, err := repository.ReadFile("HEAD", "docs/index.yaml")
+		require.NoError(t, err)
+		index := readIndex(t, content)
+		require.Len(t, index.Entries["api"], 1)
+		assert.Equal(t, []string{"api-1.2.0.tgz"}, index.Entries["api"][0].URLs)
+
+		archive, err := os.ReadFile(filepath.Join(dir, "docs", "api-1.2.0.tgz"))
+		require.NoError(t, err)
+		assert.Equal(t, pkg.Content, archive)
+	})
+}
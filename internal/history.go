@@ -0,0 +1,146 @@
+package monoreleaser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A PastRelease is a Tag of a Module together with the Commits it released.
+type PastRelease struct {
+	Tag Tag
+	// Module is the directory of the Module the Tag belongs to, like ReleaseOptions.Module.
+	Module string
+	// TagModule is the Module the Tag name is prefixed with, like ReleaseOptions.TagModule.
+	TagModule string
+	// Previous is the release before the Tag, or nil if it is the first release of the Module.
+	Previous *Tag
+	// Date is the committer date of the tagged Commit.
+	Date time.Time
+	// Commits of the Module since the Previous release, newest first.
+	Commits []*Commit
+}
+
+// Changelog regenerates the Changelog of the release from its Commits.
+func (release PastRelease) Changelog() (Changelog, error) {
+	return GenerateChangelog(Extract(release.Commits))
+}
+
+// Optional parameters for looking up past releases.
+type PastReleaseOptions struct {
+	// When FirstParent is set, the releases only contain the merge (PR) commits of the main line.
+	FirstParent bool
+	// VersionCommitMessage is the template of the message of the Commits bumping the version files, like BumpOptions.Message.
+	// A tagged Commit with this message is left out of its release, like Releasers leave out ReleaseOptions.VersionCommit.
+	// If this option is not set, DefaultVersionCommitMessage will be used.
+	VersionCommitMessage string
+}
+
+// ListReleases returns the releases of a Module, highest version first.
+func ListReleases(repository Repository, module Module, opts PastReleaseOptions) ([]PastRelease, error) {
+	tags, err := repository.GetTags(GetTagOptions{Module: module.tagModule()})
+	if err != nil {
+		return nil, err
+	}
+	tags = ownTags(tags)
+
+	releases := make([]PastRelease, 0, len(tags))
+	for i := range tags {
+		release, err := pastRelease(repository, module, tags, i, opts)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, *release)
+	}
+	return releases, nil
+}
+
+// ShowRelease returns a release of a Module and regenerates its Changelog from the Commits since the previous release.
+// The version may be passed with or without its "v" prefix.
+// Changes caused by dependency bumps are not part of the history, so that they are missing in the Changelog.
+func ShowRelease(repository Repository, module Module, version string, opts PastReleaseOptions) (*PastRelease, Changelog, error) {
+	tags, err := repository.GetTags(GetTagOptions{Module: module.tagModule()})
+	if err != nil {
+		return nil, "", err
+	}
+	tags = ownTags(tags)
+
+	i := tagIndex(tags, version)
+	if i < 0 {
+		return nil, "", fmt.Errorf("%w: %s %s", ErrTagNotFound, ModuleName(module.Path), version)
+	}
+
+	release, err := pastRelease(repository, module, tags, i, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	changelog, err := release.Changelog()
+	if err != nil {
+		return nil, "", err
+	}
+	return release, changelog, nil
+}
+
+// tagIndex returns the index of the Tag of a version, which may be passed with or without its "v" prefix, or -1 if there is none.
+func tagIndex(tags []Tag, version string) int {
+	for i, tag := range tags {
+		if SameVersion(tag.Version, version) {
+			return i
+		}
+	}
+	return -1
+}
+
+// pastRelease returns the release of the Tag at index i, whose predecessor is the following Tag, as the Tags are sorted highest first.
+func pastRelease(repository Repository, module Module, tags []Tag, i int, opts PastReleaseOptions) (*PastRelease, error) {
+	release := PastRelease{Tag: tags[i], Module: module.Path, TagModule: module.TagModule}
+	if i+1 < len(tags) {
+		release.Previous = &tags[i+1]
+	}
+
+	date, err := repository.CommitDate(release.Tag.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", release.Tag.Name, err)
+	}
+	release.Date = date
+
+	diffOpts := DiffOptions{Module: module.Path, Paths: module.Paths, FirstParent: opts.FirstParent}
+	commits, err := repository.Diff(release.Tag, release.Previous, diffOpts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", release.Tag.Name, err)
+	}
+
+	versionCommit, err := isVersionCommit(commits, module, release.Tag, opts.VersionCommitMessage)
+	if err != nil {
+		return nil, err
+	}
+	if versionCommit {
+		commits = withoutCommit(commits, release.Tag.Hash)
+	}
+	release.Commits = commits
+
+	return &release, nil
+}
+
+// isVersionCommit reports whether the tagged Commit bumped the version files of the Module,
+// whose version may have been passed with or without its "v" prefix.
+func isVersionCommit(commits []*Commit, module Module, tag Tag, text string) (bool, error) {
+	for _, commit := range commits {
+		if commit.Hash != tag.Hash {
+			continue
+		}
+
+		version := strings.TrimPrefix(tag.Version, "v")
+		for _, version := range []string{version, "v" + version} {
+			message, err := versionCommitMessage(text, module, version)
+			if err != nil {
+				return false, err
+			}
+			if strings.TrimSpace(commit.Message) == strings.TrimSpace(message) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,94 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListReleases(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		releases, err := ListReleases(repository, Module{Path: ""}, PastReleaseOptions{})
+		require.NoError(t, err)
+		require.Len(t, releases, 10)
+
+		assert.Equal(t, "v1.10.0", releases[0].Tag.Name)
+		assert.Equal(t, "v1.9.0", releases[0].Previous.Name)
+		assert.Equal(t, []*Commit{commits[10]}, releases[0].Commits)
+		assert.False(t, releases[0].Date.IsZero())
+
+		first := releases[len(releases)-1]
+		assert.Equal(t, "v1.0.0", first.Tag.Name)
+		assert.Nil(t, first.Previous)
+		assert.Equal(t, []*Commit{commits[1], commits[0]}, first.Commits)
+
+		releases, err = ListReleases(repository, Module{Path: "subdir"}, PastReleaseOptions{})
+		require.NoError(t, err)
+		require.Len(t, releases, 2)
+		assert.Equal(t, "subdir/v1.11.0", releases[0].Tag.Name)
+		assert.Equal(t, []*Commit{commits[11]}, releases[0].Commits)
+	})
+}
+
+func TestShowRelease(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		release, changelog, err := ShowRelease(repository, Module{Path: ""}, "1.10.0", PastReleaseOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "v1.10.0", release.Tag.Name)
+		assert.Equal(t, "v1.9.0", release.Previous.Name)
+
+		expected, err := GenerateChangelog(Extract([]*Commit{commits[10]}))
+		require.NoError(t, err)
+		assert.Equal(t, expected, changelog)
+
+		_, _, err = ShowRelease(repository, Module{Path: ""}, "v3.0.0", PastReleaseOptions{})
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
+
+func TestListReleases_VersionCommit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		message string
+		version string
+	}{
+		{name: "default message", version: "v1.1.0"},
+		{name: "without v", version: "1.1.0"},
+		{name: "configured message", message: "release {{.Module}}@{{.Version}}", version: "v1.1.0"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			var dir string
+			forEachImplementation(t, func(t *testing.T) string {
+				dir = versionFileRepo(t)
+				return dir
+			}, func(t *testing.T, repository Repository) {
+				module := NewConfiguredModule("web", ModulePaths{Include: []string{"subdir"}})
+				module.VersionFiles = []string{"package.json"}
+
+				commit, err := BumpVersionFiles(afero.NewOsFs(), repository, module, testCase.version, BumpOptions{Root: dir, Message: testCase.message})
+				require.NoError(t, err)
+				_, err = repository.Tag("v1.1.0", TagOptions{Module: module.Path, TagModule: module.TagModule, Hash: commit.Hash})
+				require.NoError(t, err)
+
+				releases, err := ListReleases(repository, module, PastReleaseOptions{VersionCommitMessage: testCase.message})
+				require.NoError(t, err)
+				require.NotEmpty(t, releases)
+				assert.Equal(t, "web/v1.1.0", releases[0].Tag.Name)
+				assert.NotEmpty(t, releases[0].Commits)
+				for _, released := range releases[0].Commits {
+					assert.NotEqual(t, commit.Hash, released.Hash)
+				}
+
+				// other messages are part of the release
+				releases, err = ListReleases(repository, module, PastReleaseOptions{VersionCommitMessage: "bump {{.Version}}"})
+				require.NoError(t, err)
+				assert.Equal(t, commit.Hash, releases[0].Commits[0].Hash)
+			})
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package monoreleaser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/spf13/afero"
+)
+
+var ErrHookExists = errors.New("hook exists and was not installed by monoreleaser")
+
+// hookMarker identifies hooks installed by monoreleaser, which are safe to overwrite.
+const hookMarker = "# installed by monoreleaser hooks install"
+
+// HookStatus is the outcome of installing a single hook.
+type HookStatus string
+
+const (
+	HookInstalled HookStatus = "installed"
+	HookUpdated   HookStatus = "updated"
+	HookUnchanged HookStatus = "unchanged"
+	// HookSkipped means, that a hook not installed by monoreleaser was left untouched.
+	HookSkipped HookStatus = "skipped"
+)
+
+// An InstalledHook is a hook written by InstallHooks.
+type InstalledHook struct {
+	Name   string
+	Path   string
+	Status HookStatus
+}
+
+// Optional parameters for installing git hooks.
+type HookOptions struct {
+	// Command invokes monoreleaser from the hooks.
+	// If this option is not set, "monoreleaser" will be looked up in the PATH.
+	Command string
+	// PrepareCommitMsg also installs a prepare-commit-msg hook, which adds a template listing the allowed types and scopes to the message.
+	PrepareCommitMsg bool
+	// Types are listed in the template of the prepare-commit-msg hook.
+	// If this option is not set, DefaultTypes will be used.
+	Types []Type
+	// Scopes are listed in the template of the prepare-commit-msg hook, e.g. the names of the Modules.
+	Scopes []string
+}
+
+// HooksDir returns the directory git runs the hooks of a repository from,
+// which is core.hooksPath, if it is configured, or the hooks directory inside of .git.
+func HooksDir(repository *git.Repository) (string, error) {
+	workTree, err := repository.Worktree()
+	if err != nil {
+		return "", err
+	}
+	root := workTree.Filesystem.Root()
+
+	hooksPath, err := configuredHooksPath(repository)
+	if err != nil {
+		return "", err
+	}
+	if hooksPath != "" {
+		if strings.HasPrefix(hooksPath, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, hooksPath[2:]), nil
+		}
+		// relative paths are resolved against the working tree, like git does for non bare repositories
+		if !filepath.IsAbs(hooksPath) {
+			return filepath.Join(root, hooksPath), nil
+		}
+		return hooksPath, nil
+	}
+
+	if storage, ok := repository.Storer.(*filesystem.Storage); ok {
+		return filepath.Join(storage.Filesystem().Root(), "hooks"), nil
+	}
+	return filepath.Join(root, git.GitDirName, "hooks"), nil
+}
+
+// configuredHooksPath returns core.hooksPath from the repository, global or system config, in this order of precedence.
+func configuredHooksPath(repository *git.Repository) (string, error) {
+	local, err := repository.Config()
+	if err != nil {
+		return "", err
+	}
+	if hooksPath := local.Raw.Section("core").Option("hooksPath"); hooksPath != "" {
+		return hooksPath, nil
+	}
+
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		cfg, err := config.LoadConfig(scope)
+		if err != nil {
+			return "", err
+		}
+		if hooksPath := cfg.Raw.Section("core").Option("hooksPath"); hooksPath != "" {
+			return hooksPath, nil
+		}
+	}
+
+	return "", nil
+}
+
+// InstallHooks writes a commit-msg hook linting the message with monoreleaser, and optionally a prepare-commit-msg hook, into dir.
+// Hooks installed by monoreleaser before are overwritten, any other hooks are left untouched and reported as HookSkipped.
+func InstallHooks(fsys afero.Fs, dir string, opts HookOptions) ([]InstalledHook, error) {
+	if opts.Command == "" {
+		opts.Command = "monoreleaser"
+	}
+	if len(opts.Types) == 0 {
+		opts.Types = DefaultTypes
+	}
+
+	hooks := []hookScript{{name: "commit-msg", script: commitMsgHook(opts)}}
+	if opts.PrepareCommitMsg {
+		hooks = append(hooks, hookScript{name: "prepare-commit-msg", script: prepareCommitMsgHook(opts)})
+	}
+
+	if err := fsys.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	installed := make([]InstalledHook, 0, len(hooks))
+	for _, hook := range hooks {
+		path := filepath.Join(dir, hook.name)
+		status, err := installHook(fsys, path, []byte(hook.script))
+		if err != nil {
+			return installed, fmt.Errorf("%s: %w", hook.name, err)
+		}
+		installed = append(installed, InstalledHook{Name: hook.name, Path: path, Status: status})
+	}
+
+	return installed, nil
+}
+
+type hookScript struct {
+	name   string
+	script string
+}
+
+// installHook writes the script of a hook, unless a hook not installed by monoreleaser exists.
+func installHook(fsys afero.Fs, path string, script []byte) (HookStatus, error) {
+	status := HookInstalled
+	existing, err := afero.ReadFile(fsys, path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return "", err
+	case !bytes.Contains(existing, []byte(hookMarker)):
+		return HookSkipped, nil
+	case bytes.Equal(existing, script):
+		return HookUnchanged, nil
+	default:
+		status = HookUpdated
+	}
+
+	if err := afero.WriteFile(fsys, path, script, 0o755); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of existing files
+	if err := fsys.Chmod(path, 0o755); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+func commitMsgHook(opts HookOptions) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+exec %s lint --commit-msg "$1"
+`, hookMarker, shellQuote(opts.Command))
+}
+
+func prepareCommitMsgHook(opts HookOptions) string {
+	template := "\n# type(scope)!: description\n#\n# types: " + joinTypes(opts.Types)
+	if len(opts.Scopes) != 0 {
+		template += "\n# scopes: " + strings.Join(opts.Scopes, ", ")
+	}
+
+	return fmt.Sprintf(`#!/bin/sh
+%s
+# only messages written in the editor get the template, not messages of -m, merges, squashes or amends
+[ -z "$2" ] || exit 0
+template=%s
+{ printf '%%s\n' "$template"; cat "$1"; } > "$1.monoreleaser" && mv "$1.monoreleaser" "$1"
+`, hookMarker, shellQuote(template))
+}
+
+// shellQuote quotes a string for a POSIX shell.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,138 @@
+package monoreleaser
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooksDir(t *testing.T) {
+	// no global core.hooksPath of the machine running the tests
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	hooksDir, err := HooksDir(repository)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git", "hooks"), hooksDir)
+
+	config, err := repository.Config()
+	require.NoError(t, err)
+	config.Raw.Section("core").SetOption("hooksPath", ".githooks")
+	require.NoError(t, repository.SetConfig(config))
+
+	hooksDir, err = HooksDir(repository)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".githooks"), hooksDir)
+
+	config.Raw.Section("core").SetOption("hooksPath", "/etc/githooks")
+	require.NoError(t, repository.SetConfig(config))
+
+	hooksDir, err = HooksDir(repository)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/githooks", hooksDir)
+}
+
+func TestInstallHooks(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	dir := "/repo/.git/hooks"
+
+	hooks, err := InstallHooks(fs, dir, HookOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []InstalledHook{{Name: "commit-msg", Path: dir + "/commit-msg", Status: HookInstalled}}, hooks)
+
+	script, err := afero.ReadFile(fs, dir+"/commit-msg")
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\n"+hookMarker+"\nexec 'monoreleaser' lint --commit-msg \"$1\"\n", string(script))
+	info, err := fs.Stat(dir + "/commit-msg")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+	// installing again is a no-op
+	hooks, err = InstallHooks(fs, dir, HookOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, HookUnchanged, hooks[0].Status)
+
+	hooks, err = InstallHooks(fs, dir, HookOptions{Command: "/usr/local/bin/monoreleaser", PrepareCommitMsg: true})
+	require.NoError(t, err)
+	assert.Equal(t, []InstalledHook{
+		{Name: "commit-msg", Path: dir + "/commit-msg", Status: HookUpdated},
+		{Name: "prepare-commit-msg", Path: dir + "/prepare-commit-msg", Status: HookInstalled},
+	}, hooks)
+}
+
+func TestInstallHooks_ExistingHook(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	dir := "/repo/.git/hooks"
+	existing := "#!/bin/sh\nnpx commitlint --edit \"$1\"\n"
+	require.NoError(t, afero.WriteFile(fs, dir+"/commit-msg", []byte(existing), 0o755))
+
+	hooks, err := InstallHooks(fs, dir, HookOptions{PrepareCommitMsg: true})
+	require.NoError(t, err)
+	assert.Equal(t, []InstalledHook{
+		{Name: "commit-msg", Path: dir + "/commit-msg", Status: HookSkipped},
+		{Name: "prepare-commit-msg", Path: dir + "/prepare-commit-msg", Status: HookInstalled},
+	}, hooks)
+
+	script, err := afero.ReadFile(fs, dir+"/commit-msg")
+	require.NoError(t, err)
+	assert.Equal(t, existing, string(script))
+}
+
+func TestInstallHooks_PrepareCommitMsg(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	dir := t.TempDir()
+	_, err := InstallHooks(afero.NewOsFs(), dir, HookOptions{
+		PrepareCommitMsg: true,
+		Types:            []Type{Feature, Fix},
+		Scopes:           []string{"api", "it's-web"},
+	})
+	require.NoError(t, err)
+
+	messageFile := filepath.Join(dir, "COMMIT_EDITMSG")
+	gitComments := "# Please enter the commit message for your changes.\n"
+
+	testCases := []struct {
+		source   string
+		message  string
+		expected string
+	}{
+		{
+			source:   "",
+			message:  "\n" + gitComments,
+			expected: "\n# type(scope)!: description\n#\n# types: feat, fix\n# scopes: api, it's-web\n\n" + gitComments,
+		},
+		{
+			source:   "message",
+			message:  "fix: passed with -m\n" + gitComments,
+			expected: "fix: passed with -m\n" + gitComments,
+		},
+	}
+
+	for _, testCase := range testCases {
+		require.NoError(t, os.WriteFile(messageFile, []byte(testCase.message), 0o644))
+
+		args := []string{filepath.Join(dir, "prepare-commit-msg"), messageFile}
+		if testCase.source != "" {
+			args = append(args, testCase.source)
+		}
+		output, err := exec.Command("sh", args...).CombinedOutput()
+		require.NoError(t, err, string(output))
+
+		message, err := os.ReadFile(messageFile)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, string(message))
+	}
+}
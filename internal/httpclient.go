@@ -0,0 +1,199 @@
+package monoreleaser
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// A RetryPolicy decides how often and how long requests to the API of a provider are retried after temporary failures.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero disables retries.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, which doubles with every further retry.
+	// A random jitter of up to half of the delay spreads the retries of concurrent clients.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two retries.
+	MaxDelay time.Duration
+	// MaxWait is the longest time a rate limited request waits for the rate limit to reset.
+	// Requests whose rate limit resets later are not retried.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy rides out short outages and the secondary rate limit of GitHub, which usually resets within a minute.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  time.Second,
+	MaxDelay:   30 * time.Second,
+	MaxWait:    time.Minute,
+}
+
+// An HTTPClient sends requests to the API of a provider and retries them after temporary failures according to a RetryPolicy.
+// Requests are cancelled by their context, which also ends the wait between two retries.
+type HTTPClient struct {
+	client *http.Client
+	policy RetryPolicy
+}
+
+// NewHTTPClient creates an HTTPClient sending the requests with the given http.Client.
+func NewHTTPClient(client *http.Client, policy RetryPolicy) HTTPClient {
+	return HTTPClient{client: client, policy: policy}
+}
+
+// Optional parameters for sending a request.
+type RequestOptions struct {
+	// Idempotent marks a request of a method like POST as safe to send more than once,
+	// e.g. because the provider refuses duplicates of the resource it creates.
+	// Requests of the idempotent methods GET, HEAD, OPTIONS, TRACE, PUT and DELETE are always safe to send more than once.
+	Idempotent bool
+}
+
+// Do sends a request and retries it after temporary failures.
+// Rate limited requests have not been processed, so that they are retried regardless of their method,
+// after the time Retry-After or X-RateLimit-Reset tell, or after the backoff delay if the provider did not tell.
+// Server errors and broken connections are only retried for idempotent requests, as the request may have been processed.
+// Requests, whose body can not be read again (see http.Request.GetBody), are not retried.
+// The response of the last attempt is returned, which may be unsuccessful.
+func (client HTTPClient) Do(request *http.Request, opts RequestOptions) (*http.Response, error) {
+	ctx := request.Context()
+	idempotent := opts.Idempotent || isIdempotent(request.Method)
+
+	for attempt := 0; ; attempt++ {
+		response, err := client.client.Do(request)
+		delay, retry := client.retryDelay(attempt, idempotent, response, err, time.Now())
+		if !retry || ctx.Err() != nil || (request.Body != nil && request.Body != http.NoBody && request.GetBody == nil) {
+			return response, err
+		}
+
+		if response != nil {
+			// drained bodies keep the connection reusable
+			io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		request, err = rewind(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt, or false if the outcome of an attempt is not retried.
+func (client HTTPClient) retryDelay(attempt int, idempotent bool, response *http.Response, err error, now time.Time) (time.Duration, bool) {
+	if attempt >= client.policy.MaxRetries {
+		return 0, false
+	}
+
+	if err != nil {
+		return client.backoff(attempt), idempotent
+	}
+
+	if reset, limited := rateLimitReset(response, now); limited {
+		if reset.IsZero() {
+			return client.backoff(attempt), true
+		}
+		wait := reset.Sub(now)
+		if wait > client.policy.MaxWait {
+			return 0, false
+		}
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	// 501 Not Implemented fails for good
+	if response.StatusCode >= 500 && response.StatusCode != http.StatusNotImplemented {
+		return client.backoff(attempt), idempotent
+	}
+
+	return 0, false
+}
+
+// backoff returns the exponential delay of an attempt with a random jitter of up to half of the delay.
+func (client HTTPClient) backoff(attempt int) time.Duration {
+	delay := client.policy.BaseDelay
+	// doubling stops at the cap, before the delay overflows
+	for i := 0; i < attempt && delay < client.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > client.policy.MaxDelay {
+		delay = client.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// rewind returns a copy of a request, whose body is read again from the start.
+func rewind(request *http.Request) (*http.Request, error) {
+	rewound := request.Clone(request.Context())
+	if request.GetBody == nil {
+		return rewound, nil
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	rewound.Body = body
+	return rewound, nil
+}
+
+// sleep waits for the given duration, unless the context is done before.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitReset returns the time a rate limited response allows to retry, or false if the response is not rate limited.
+// Secondary rate limits send Retry-After, primary rate limits exhaust X-RateLimit-Remaining until X-RateLimit-Reset.
+func rateLimitReset(response *http.Response, now time.Time) (time.Time, bool) {
+	if response.StatusCode != http.StatusForbidden && response.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+
+	if retryAfter := response.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return now.Add(time.Duration(seconds) * time.Second), true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return date, true
+		}
+		return time.Time{}, true
+	}
+
+	if response.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, false
+	}
+	if reset, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(reset, 0), true
+	}
+	return time.Time{}, true
+}
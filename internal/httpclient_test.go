@@ -0,0 +1,191 @@
+package monoreleaser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingServer fails the first failures requests with the response of fail, before it echoes the request body.
+// It returns the number of received requests.
+func failingServer(t *testing.T, failures int32, fail http.HandlerFunc) (*httptest.Server, *int32) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			fail(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		w.Write(body)
+	}))
+	return ts, &requests
+}
+
+func respond(status int, header http.Header) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+	}
+}
+
+// resetConnection closes the connection without a response, like a connection reset by the peer.
+func resetConnection(w http.ResponseWriter, r *http.Request) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func TestHTTPClient_Do(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		opts       RequestOptions
+		failures   int32
+		fail       http.HandlerFunc
+		expected   int
+		requests   int32
+		minElapsed time.Duration
+	}{
+		{name: "server error", method: http.MethodGet, failures: 2, fail: respond(http.StatusBadGateway, nil), expected: http.StatusOK, requests: 3},
+		{name: "connection reset", method: http.MethodGet, failures: 2, fail: resetConnection, expected: http.StatusOK, requests: 3},
+		{name: "exhausted", method: http.MethodGet, failures: 5, fail: respond(http.StatusServiceUnavailable, nil), expected: http.StatusServiceUnavailable, requests: 4},
+		{name: "not implemented", method: http.MethodGet, failures: 1, fail: respond(http.StatusNotImplemented, nil), expected: http.StatusNotImplemented, requests: 1},
+		{name: "client error", method: http.MethodGet, failures: 1, fail: respond(http.StatusNotFound, nil), expected: http.StatusNotFound, requests: 1},
+		{name: "not idempotent", method: http.MethodPost, failures: 1, fail: respond(http.StatusInternalServerError, nil), expected: http.StatusInternalServerError, requests: 1},
+		{
+			name:     "idempotent post",
+			method:   http.MethodPost,
+			opts:     RequestOptions{Idempotent: true},
+			failures: 2,
+			fail:     respond(http.StatusInternalServerError, nil),
+			expected: http.StatusOK,
+			requests: 3,
+		},
+		{
+			name:       "retry after",
+			method:     http.MethodPost,
+			failures:   1,
+			fail:       respond(http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}}),
+			expected:   http.StatusOK,
+			requests:   2,
+			minElapsed: time.Second,
+		},
+		{
+			name:     "secondary rate limit without reset",
+			method:   http.MethodPost,
+			failures: 1,
+			fail:     respond(http.StatusForbidden, http.Header{"Retry-After": {"soon"}}),
+			expected: http.StatusOK,
+			requests: 2,
+		},
+		{
+			name:     "rate limit resets too late",
+			method:   http.MethodGet,
+			failures: 1,
+			fail: respond(http.StatusForbidden, http.Header{
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {"32503680000"},
+			}),
+			expected: http.StatusForbidden,
+			requests: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			ts, requests := failingServer(t, testCase.failures, testCase.fail)
+			defer ts.Close()
+
+			request, err := http.NewRequest(testCase.method, ts.URL, strings.NewReader("release"))
+			require.NoError(t, err)
+
+			client := NewHTTPClient(ts.Client(), testRetryPolicy)
+			start := time.Now()
+			response, err := client.Do(request, testCase.opts)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			assert.Equal(t, testCase.expected, response.StatusCode)
+			assert.Equal(t, testCase.requests, atomic.LoadInt32(requests))
+			assert.GreaterOrEqual(t, time.Since(start), testCase.minElapsed)
+			if response.StatusCode == http.StatusOK {
+				// the body is sent again with every retry
+				body, err := io.ReadAll(response.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, "release", string(body))
+			}
+		})
+	}
+}
+
+func TestHTTPClient_Do_ConnectionResetExhausted(t *testing.T) {
+	ts, requests := failingServer(t, 10, resetConnection)
+	defer ts.Close()
+
+	request, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+
+	_, err = NewHTTPClient(ts.Client(), testRetryPolicy).Do(request, RequestOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+}
+
+func TestHTTPClient_Do_UnreadableBody(t *testing.T) {
+	ts, requests := failingServer(t, 1, respond(http.StatusBadGateway, nil))
+	defer ts.Close()
+
+	// a reader without GetBody can only be sent once
+	request, err := http.NewRequest(http.MethodPut, ts.URL, io.MultiReader(bytes.NewBufferString("release")))
+	require.NoError(t, err)
+
+	response, err := NewHTTPClient(ts.Client(), testRetryPolicy).Do(request, RequestOptions{})
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, response.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestHTTPClient_Do_Cancelled(t *testing.T) {
+	ts, requests := failingServer(t, 10, respond(http.StatusServiceUnavailable, nil))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	start := time.Now()
+	_, err = NewHTTPClient(ts.Client(), policy).Do(request, RequestOptions{})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestHTTPClient_backoff(t *testing.T) {
+	client := NewHTTPClient(http.DefaultClient, RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second})
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		delay := client.backoff(attempt)
+		assert.GreaterOrEqual(t, delay, expected/2, attempt)
+		assert.LessOrEqual(t, delay, expected, attempt)
+	}
+
+	// the delay is capped instead of overflowing
+	assert.LessOrEqual(t, client.backoff(100), 5*time.Second)
+	assert.GreaterOrEqual(t, client.backoff(100), 5*time.Second/2)
+}
@@ -0,0 +1,193 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrUnknownType = errors.New("unknown commit type")
+
+// DefaultTypes are the commit types allowed by LintMessage, if no others are configured.
+// They are all types Extract derives a Semantic from.
+var DefaultTypes = []Type{Feature, Fix, Build, Chore, Ci, Docs, Style, Refactor, Perf, Test, RevertType}
+
+// ParseTypes returns the Types of the given names, which are restricted to the DefaultTypes,
+// as the Changes of Commits with other types would be Unknown and therefore left out of the changelog.
+func ParseTypes(names []string) ([]Type, error) {
+	types := make([]Type, 0, len(names))
+	for _, name := range names {
+		typ := Type(name)
+		if !containsType(DefaultTypes, typ) {
+			return nil, fmt.Errorf("%w: %s (expected one of %s)", ErrUnknownType, name, joinTypes(DefaultTypes))
+		}
+		types = append(types, typ)
+	}
+	return types, nil
+}
+
+// A LintIssue is a violation of the Conventional Commits specification by a commit message.
+type LintIssue struct {
+	// Hash of the Commit, which is empty for messages of Commits not created yet, e.g. in a commit-msg hook.
+	Hash string `json:"hash,omitempty"`
+	// Subject is the first line of the message.
+	Subject string `json:"subject"`
+	// Line of the message the issue was found in, starting at 1.
+	Line int `json:"line"`
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// Optional parameters for linting commit messages.
+type LintOptions struct {
+	// Types are the allowed commit types, a subset of the DefaultTypes (see ParseTypes).
+	// If this option is not set, DefaultTypes will be used.
+	Types []Type
+	// MaxHeaderLength is the maximum length of the first line of a message.
+	// If this option is not set, the length is not limited.
+	MaxHeaderLength int
+}
+
+// headerPattern matches the header of a Conventional Commit like type(scope)!: description.
+var headerPattern = regexp.MustCompile(`^([^\s(!:]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// scissorsLine is the line git adds in verbose mode, below which everything is removed from the message.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// CleanMessage removes the comments from a message, like git does after the commit-msg hook has been run.
+func CleanMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == scissorsLine {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// LintMessage validates a message against the Conventional Commits specification and the allowed types.
+// Merge commits, reverts created by git and fixup commits are not linted, as their messages are generated.
+func LintMessage(message string, opts LintOptions) []LintIssue {
+	if len(opts.Types) == 0 {
+		opts.Types = DefaultTypes
+	}
+
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	header := strings.TrimRight(lines[0], "\r")
+	issue := func(line int, format string, args ...any) LintIssue {
+		return LintIssue{Subject: header, Line: line, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if strings.TrimSpace(message) == "" {
+		return []LintIssue{issue(1, "message is empty")}
+	}
+	if isGenerated(header) {
+		return nil
+	}
+
+	var issues []LintIssue
+	if opts.MaxHeaderLength > 0 && len(header) > opts.MaxHeaderLength {
+		issues = append(issues, issue(1, "header is longer than %d characters", opts.MaxHeaderLength))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		issues = append(issues, issue(2, "body must be separated from the header by a blank line"))
+	}
+
+	match := headerPattern.FindStringSubmatchIndex(header)
+	if match == nil {
+		return append(issues, issue(1, "header must look like type(scope)!: description"))
+	}
+	group := func(i int) string {
+		if match[2*i] < 0 {
+			return ""
+		}
+		return header[match[2*i]:match[2*i+1]]
+	}
+
+	if typ := Type(group(1)); !containsType(opts.Types, typ) {
+		issues = append(issues, issue(1, "type %q is not one of %s", typ, joinTypes(opts.Types)))
+	}
+	// parentheses in the description do not make up a scope
+	if scopeMatched := match[4] >= 0; scopeMatched && strings.TrimSpace(group(2)) == "" {
+		issues = append(issues, issue(1, "scope must not be empty"))
+	}
+	if strings.TrimSpace(group(4)) == "" {
+		issues = append(issues, issue(1, "description must not be empty"))
+	}
+
+	return issues
+}
+
+// isGenerated reports whether git or a hosting service generated the header.
+func isGenerated(header string) bool {
+	return strings.HasPrefix(header, "Merge ") ||
+		strings.HasPrefix(header, RevertPrefix) ||
+		strings.HasPrefix(header, "fixup! ") ||
+		strings.HasPrefix(header, "squash! ") ||
+		strings.HasPrefix(header, "amend! ")
+}
+
+func containsType(types []Type, typ Type) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func joinTypes(types []Type) string {
+	names := make([]string, 0, len(types))
+	for _, typ := range types {
+		names = append(names, string(typ))
+	}
+	return strings.Join(names, ", ")
+}
+
+// LintCommits lints the messages of Commits and notes their hashes in the LintIssues.
+func LintCommits(commits []*Commit, opts LintOptions) []LintIssue {
+	var issues []LintIssue
+	for _, commit := range commits {
+		for _, issue := range LintMessage(commit.Message, opts) {
+			issue.Hash = commit.Hash
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// LintRange lints the Commits reachable from the revision to, but not from the revision from.
+// If from is empty, the whole history reachable from to is linted.
+func LintRange(repository Repository, from string, to string, opts LintOptions) ([]LintIssue, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+
+	toHash, err := repository.ResolveRevision(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromTag *Tag
+	if from != "" {
+		fromHash, err := repository.ResolveRevision(from)
+		if err != nil {
+			return nil, err
+		}
+		fromTag = &Tag{Hash: fromHash}
+	}
+
+	commits, err := repository.Diff(Tag{Hash: toHash}, fromTag, DiffOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return LintCommits(commits, opts), nil
+}
@@ -0,0 +1,104 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintMessage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		opts     LintOptions
+		expected []string
+	}{
+		{name: "type", message: "feat: add login"},
+		{name: "scope", message: "fix(auth): handle expired tokens"},
+		{name: "parentheses in description", message: "fix: handle nil (closes #12)"},
+		{name: "breaking", message: "refactor(api)!: drop v1 endpoints\n\nBREAKING CHANGE: v1 is gone"},
+		{name: "body", message: "docs: explain setup\n\nLonger explanation.\n"},
+		{name: "merge", message: "Merge pull request #1 from kharf/feature"},
+		{name: "git revert", message: "Revert \"feat: add login\"\n\nThis reverts commit abc."},
+		{name: "fixup", message: "fixup! feat: add login"},
+		{name: "empty", message: "\n", expected: []string{"message is empty"}},
+		{name: "no type", message: "add login", expected: []string{"header must look like type(scope)!: description"}},
+		{name: "missing space", message: "feat:add login", expected: []string{"header must look like type(scope)!: description"}},
+		{name: "unknown type", message: "feature: add login", expected: []string{`type "feature" is not one of feat, fix, build, chore, ci, docs, style, refactor, perf, test, revert`}},
+		{name: "empty scope", message: "feat(): add login", expected: []string{"scope must not be empty"}},
+		{name: "empty description", message: "feat:  ", expected: []string{"description must not be empty"}},
+		{name: "body without blank line", message: "feat: multi line\nbody", expected: []string{"body must be separated from the header by a blank line"}},
+		{
+			name:     "configured types",
+			message:  "docs: explain setup",
+			opts:     LintOptions{Types: []Type{Feature, Fix}},
+			expected: []string{`type "docs" is not one of feat, fix`},
+		},
+		{
+			name:     "header length",
+			message:  "feat: a very long description",
+			opts:     LintOptions{MaxHeaderLength: 10},
+			expected: []string{"header is longer than 10 characters"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			var messages []string
+			for _, issue := range LintMessage(testCase.message, testCase.opts) {
+				messages = append(messages, issue.Message)
+			}
+			assert.Equal(t, testCase.expected, messages)
+		})
+	}
+}
+
+func TestCleanMessage(t *testing.T) {
+	message := `feat: add login
+
+Body
+# Please enter the commit message for your changes.
+#
+# ------------------------ >8 ------------------------
+diff --git a/login.go b/login.go
+`
+	assert.Equal(t, "feat: add login\n\nBody", CleanMessage(message))
+}
+
+func TestParseTypes(t *testing.T) {
+	types, err := ParseTypes([]string{"feat", "fix", "revert"})
+	require.NoError(t, err)
+	assert.Equal(t, []Type{Feature, Fix, RevertType}, types)
+
+	// Extract would not derive a Semantic from the type
+	_, err = ParseTypes([]string{"feat", "security"})
+	assert.ErrorIs(t, err, ErrUnknownType)
+	assert.Contains(t, err.Error(), "security")
+}
+
+func TestLintRange(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		issues, err := LintRange(repository, "", "", LintOptions{})
+		require.NoError(t, err)
+		require.Len(t, issues, 1)
+		assert.Equal(t, commits[lenCommits-2].Hash, issues[0].Hash)
+		assert.Equal(t, "feat: multi line", issues[0].Subject)
+		assert.Equal(t, 2, issues[0].Line)
+
+		issues, err = LintRange(repository, "HEAD~1", "HEAD", LintOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+
+		// the two commits before HEAD
+		issues, err = LintRange(repository, "HEAD~3", "HEAD~1", LintOptions{Types: []Type{Feature}})
+		require.NoError(t, err)
+		require.Len(t, issues, 2)
+		assert.Equal(t, "feat: multi line", issues[0].Subject)
+		assert.Equal(t, "build: another change", issues[1].Subject)
+
+		_, err = LintRange(repository, "unknown", "", LintOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,338 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+var ErrDuplicateModule = errors.New("modules share the same directory")
+
+// DefaultModuleMarkers are the files which mark a directory as a Module.
+var DefaultModuleMarkers = []string{"go.mod", "package.json", "Cargo.toml", "Chart.yaml", "pom.xml"}
+
+// ignoredDirs are never searched for Modules, because they contain dependencies or test data.
+var ignoredDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"testdata":     true,
+}
+
+// A Module is just an application (directory) inside a mono repository.
+type Module struct {
+	// Path of the Module directory relative to the repository root.
+	// The repository root itself has an empty Path.
+	Path string
+	// Markers are the marker files found in the Module directory.
+	Markers []string
+	// TagModule is the Module the Tags are prefixed with, if it differs from the Path, like TagOptions.TagModule.
+	TagModule string
+	// Name is the logical name of a configured Module, which it is released and tagged as.
+	// Discovered Modules are named by their Path.
+	Name string
+	// Paths replace the Path as the filter of the Commits, like DiffOptions.Paths.
+	Paths ModulePaths
+	// VersionFiles are the files relative to the Path, which the version is written into before it is tagged.
+	// Each file needs a VersionUpdater for its name.
+	VersionFiles []string
+	// Chart marks the Module as a Helm chart, whose Chart.yaml is bumped and which is packaged on release.
+	Chart bool
+	// VersionSource is the source of truth of the version the Module is released with.
+	// If it is not set, VersionSourceTags will be used.
+	VersionSource VersionSource
+	// Assets are the patterns (see path.Match) of the files, which have to be attached to a draft release of the Module,
+	// before it is published.
+	Assets []string
+}
+
+// NewConfiguredModule creates a Module with a logical name, which consists of the given Paths.
+// The first included directory is the Path of the Module, where its manifests are located.
+func NewConfiguredModule(name string, paths ModulePaths) Module {
+	var path string
+	if len(paths.Include) != 0 {
+		path = cleanDirs(paths.Include[:1])[0]
+	}
+
+	return Module{
+		Path:      path,
+		TagModule: name,
+		Name:      name,
+		Paths:     paths,
+	}
+}
+
+// tagModule returns the Module the Tags of this Module are prefixed with.
+func (module Module) tagModule() string {
+	return resolveTagModule(module.Path, module.TagModule)
+}
+
+// Optional parameters for discovering Modules.
+type DiscoverOptions struct {
+	// Root is the directory of the repository.
+	// If this option is not set, the current directory will be used.
+	Root string
+	// Markers are the files which mark a directory as a Module.
+	// If this option is not set, DefaultModuleMarkers will be used.
+	Markers []string
+	// Globs explicitly select the Module directories, e.g. "services/*".
+	// If this option is set, directories are not searched for Markers.
+	Globs []string
+	// Modules are configured Modules, which replace discovered Modules with the same Path.
+	Modules []Module
+}
+
+// DiscoverModules finds all Modules of a repository, sorted by Path.
+// Hidden directories and directories containing dependencies are skipped.
+func DiscoverModules(fsys afero.Fs, opts DiscoverOptions) ([]Module, error) {
+	if opts.Root == "" {
+		opts.Root = "."
+	}
+	if len(opts.Markers) == 0 {
+		opts.Markers = DefaultModuleMarkers
+	}
+
+	var modules []Module
+	var err error
+	if len(opts.Globs) != 0 {
+		modules, err = globModules(fsys, opts)
+	} else {
+		modules, err = markedModules(fsys, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err = addConfiguredModules(fsys, opts, modules)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+
+	return modules, nil
+}
+
+func markedModules(fsys afero.Fs, opts DiscoverOptions) ([]Module, error) {
+	var modules []Module
+	err := afero.Walk(fsys, opts.Root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		if path != opts.Root && (strings.HasPrefix(info.Name(), ".") || ignoredDirs[info.Name()]) {
+			return filepath.SkipDir
+		}
+
+		markers, err := findMarkers(fsys, path, opts.Markers)
+		if err != nil {
+			return err
+		}
+		if len(markers) == 0 {
+			return nil
+		}
+
+		modulePath, err := relativeModulePath(opts.Root, path)
+		if err != nil {
+			return err
+		}
+		module, err := newModule(fsys, opts.Root, modulePath, markers)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, module)
+
+		return nil
+	})
+
+	return modules, err
+}
+
+func globModules(fsys afero.Fs, opts DiscoverOptions) ([]Module, error) {
+	seen := make(map[string]bool)
+	var modules []Module
+	for _, glob := range opts.Globs {
+		matches, err := afero.Glob(fsys, filepath.Join(opts.Root, glob))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range matches {
+			isDir, err := afero.IsDir(fsys, match)
+			if err != nil {
+				return nil, err
+			}
+			if !isDir {
+				continue
+			}
+
+			modulePath, err := relativeModulePath(opts.Root, match)
+			if err != nil {
+				return nil, err
+			}
+			if seen[modulePath] {
+				continue
+			}
+			seen[modulePath] = true
+
+			markers, err := findMarkers(fsys, match, opts.Markers)
+			if err != nil {
+				return nil, err
+			}
+			module, err := newModule(fsys, opts.Root, modulePath, markers)
+			if err != nil {
+				return nil, err
+			}
+			modules = append(modules, module)
+		}
+	}
+
+	return modules, nil
+}
+
+// addConfiguredModules replaces the discovered Modules with the configured ones of the same Path and adds the others.
+func addConfiguredModules(fsys afero.Fs, opts DiscoverOptions, discovered []Module) ([]Module, error) {
+	if len(opts.Modules) == 0 {
+		return discovered, nil
+	}
+
+	configured := make(map[string]bool, len(opts.Modules))
+	modules := make([]Module, 0, len(discovered)+len(opts.Modules))
+	for _, module := range opts.Modules {
+		markers, err := findMarkers(fsys, filepath.Join(opts.Root, module.Path), opts.Markers)
+		if err != nil {
+			return nil, err
+		}
+		module.Markers = markers
+		if configured[module.Path] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateModule, ModuleName(module.Path))
+		}
+		configured[module.Path] = true
+		modules = append(modules, module)
+	}
+
+	for _, module := range discovered {
+		if !configured[module.Path] {
+			modules = append(modules, module)
+		}
+	}
+
+	return modules, nil
+}
+
+// newModule creates a Module and determines the Module its Tags are prefixed with from the go.mod file, if there is one.
+func newModule(fsys afero.Fs, root string, path string, markers []string) (Module, error) {
+	module := Module{Path: path, Markers: markers}
+	for _, marker := range markers {
+		if marker != "go.mod" {
+			continue
+		}
+
+		goModule, err := ReadGoModule(fsys, root, path)
+		if errors.Is(err, ErrNoGoModule) {
+			break
+		}
+		if err != nil {
+			return Module{}, err
+		}
+		module.TagModule = goModule.TagModule()
+	}
+
+	return module, nil
+}
+
+func findMarkers(fsys afero.Fs, dir string, markers []string) ([]string, error) {
+	var found []string
+	for _, marker := range markers {
+		exists, err := afero.Exists(fsys, filepath.Join(dir, marker))
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			found = append(found, marker)
+		}
+	}
+
+	return found, nil
+}
+
+// relativeModulePath converts a directory to a Module Path, which always uses forward slashes like git.
+func relativeModulePath(root string, dir string) (string, error) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return "", nil
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// Unreleased returns the latest Tag of a Module and the Commits of the Module since, newest first.
+// The latest Tag is nil, if the Module has never been released.
+func Unreleased(repository Repository, module Module, opts DiffOptions) (*Tag, []*Commit, error) {
+	head, err := repository.Head()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	latestTag, err := LatestTag(repository, module.tagModule())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts.Module = module.Path
+	opts.Paths = module.Paths
+	commits, err := repository.Diff(Tag{Hash: head}, latestTag, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return latestTag, commits, nil
+}
+
+// LatestTag returns the Tag with the highest version of a Module, or nil if the Module has never been released.
+// The Module is the one the Tags are prefixed with.
+func LatestTag(repository Repository, module string) (*Tag, error) {
+	tags, err := repository.GetTags(GetTagOptions{Module: module})
+	if err != nil {
+		return nil, err
+	}
+
+	tags = ownTags(tags)
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return &tags[0], nil
+}
+
+// ModuleName returns a readable name for a Module Path, which is how it is passed on the command line as well,
+// where "." is the repository root.
+func ModuleName(module string) string {
+	if module == "" {
+		return "."
+	}
+	return module
+}
+
+// ownTags returns the Tags, which do not belong to nested Modules.
+// Tags of nested Modules share the prefix of their parent Module, so that GetTags returns them as well.
+func ownTags(tags []Tag) []Tag {
+	own := make([]Tag, 0, len(tags))
+	for _, tag := range tags {
+		if !strings.Contains(tag.Version, "/") {
+			own = append(own, tag)
+		}
+	}
+	return own
+}
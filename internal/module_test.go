@@ -0,0 +1,197 @@
+package monoreleaser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newModuleFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	files := []string{
+		"go.mod",
+		"README.md",
+		"libs/auth/go.mod",
+		"libs/ui/package.json",
+		"libs/ui/node_modules/react/package.json",
+		"services/api/go.mod",
+		"services/api/Chart.yaml",
+		"services/api/vendor/github.com/lib/go.mod",
+		"services/web/Cargo.toml",
+		"services/legacy/pom.xml",
+		"services/docs/README.md",
+		".github/actions/release/package.json",
+		"internal/testdata/go.mod",
+	}
+	for _, file := range files {
+		require.NoError(t, afero.WriteFile(fs, file, []byte{}, 0o644))
+	}
+	return fs
+}
+
+func TestDiscoverModules_Markers(t *testing.T) {
+	modules, err := DiscoverModules(newModuleFs(t), DiscoverOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Path: "", Markers: []string{"go.mod"}},
+		{Path: "libs/auth", Markers: []string{"go.mod"}},
+		{Path: "libs/ui", Markers: []string{"package.json"}},
+		{Path: "services/api", Markers: []string{"go.mod", "Chart.yaml"}},
+		{Path: "services/legacy", Markers: []string{"pom.xml"}},
+		{Path: "services/web", Markers: []string{"Cargo.toml"}},
+	}, modules)
+}
+
+func TestDiscoverModules_CustomMarkers(t *testing.T) {
+	modules, err := DiscoverModules(newModuleFs(t), DiscoverOptions{Markers: []string{"Chart.yaml"}})
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Path: "services/api", Markers: []string{"Chart.yaml"}},
+	}, modules)
+}
+
+func TestDiscoverModules_Globs(t *testing.T) {
+	modules, err := DiscoverModules(newModuleFs(t), DiscoverOptions{Globs: []string{"services/*", "libs/auth", "README.md"}})
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Path: "libs/auth", Markers: []string{"go.mod"}},
+		{Path: "services/api", Markers: []string{"go.mod", "Chart.yaml"}},
+		{Path: "services/docs"},
+		{Path: "services/legacy", Markers: []string{"pom.xml"}},
+		{Path: "services/web", Markers: []string{"Cargo.toml"}},
+	}, modules)
+}
+
+func TestDiscoverModules_Root(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "repo/go.mod", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "repo/libs/auth/go.mod", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "other/go.mod", []byte{}, 0o644))
+
+	modules, err := DiscoverModules(fs, DiscoverOptions{Root: "repo"})
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Path: "", Markers: []string{"go.mod"}},
+		{Path: "libs/auth", Markers: []string{"go.mod"}},
+	}, modules)
+}
+
+func TestDiscoverModules_Configured(t *testing.T) {
+	api := NewConfiguredModule("api", ModulePaths{Include: []string{"services/api/", "libs/auth"}})
+	docs := NewConfiguredModule("docs", ModulePaths{Include: []string{"services/docs"}})
+	modules, err := DiscoverModules(newModuleFs(t), DiscoverOptions{Markers: []string{"go.mod"}, Modules: []Module{api, docs}})
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Path: "", Markers: []string{"go.mod"}},
+		{Path: "libs/auth", Markers: []string{"go.mod"}},
+		{
+			Path:      "services/api",
+			Markers:   []string{"go.mod"},
+			TagModule: "api",
+			Name:      "api",
+			Paths:     ModulePaths{Include: []string{"services/api/", "libs/auth"}},
+		},
+		{
+			Path:      "services/docs",
+			TagModule: "docs",
+			Name:      "docs",
+			Paths:     ModulePaths{Include: []string{"services/docs"}},
+		},
+	}, modules)
+}
+
+func TestDiscoverModules_ConfiguredDuplicate(t *testing.T) {
+	api := NewConfiguredModule("api", ModulePaths{Include: []string{"services/api"}})
+	gateway := NewConfiguredModule("gateway", ModulePaths{Include: []string{"services/api/"}})
+	_, err := DiscoverModules(newModuleFs(t), DiscoverOptions{Modules: []Module{api, gateway}})
+	assert.ErrorIs(t, err, ErrDuplicateModule)
+}
+
+// unreleasedRepo returns a setup function for forEachImplementation, which creates a new repository with an unreleased Commit in subdir.
+func unreleasedRepo(unreleased **Commit) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		dir := t.TempDir()
+		repository, _, _, _ := newRepo(dir, false)
+
+		workTree, err := repository.repository.Worktree()
+		require.NoError(t, err)
+		file, err := workTree.Filesystem.Create("subdir/unreleased")
+		require.NoError(t, err)
+		file.Close()
+		_, err = workTree.Add(file.Name())
+		require.NoError(t, err)
+
+		message := "fix: unreleased"
+		hash, err := workTree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{
+				Name:  "orca",
+				Email: "orca-dev@mail.com",
+				When:  time.Now(),
+			},
+		})
+		require.NoError(t, err)
+		*unreleased = &Commit{Hash: hash.String(), Message: message}
+
+		return dir
+	}
+}
+
+func TestUnreleased_Module(t *testing.T) {
+	var unreleased *Commit
+	forEachImplementation(t, unreleasedRepo(&unreleased), func(t *testing.T, repository Repository) {
+		latestTag, commits, err := Unreleased(repository, Module{Path: "subdir"}, DiffOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, latestTag)
+		assert.Equal(t, "subdir/v1.11.0", latestTag.Name)
+		assert.Equal(t, []*Commit{unreleased}, commits)
+	})
+}
+
+func TestUnreleased_RootIgnoresModuleTags(t *testing.T) {
+	var unreleased *Commit
+	forEachImplementation(t, unreleasedRepo(&unreleased), func(t *testing.T, repository Repository) {
+		latestTag, commits, err := Unreleased(repository, Module{Path: ""}, DiffOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, latestTag)
+		assert.Equal(t, "v1.10.0", latestTag.Name)
+		assert.Len(t, commits, 2)
+		assert.Equal(t, unreleased, commits[0])
+	})
+}
+
+func TestUnreleased_NoChanges(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		latestTag, commits, err := Unreleased(repository, Module{Path: "subdir"}, DiffOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, latestTag)
+		assert.Equal(t, tags[lenCommits-1].Name, latestTag.Name)
+		assert.Empty(t, commits)
+	})
+}
+
+func TestUnreleased_NeverReleased(t *testing.T) {
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(false, &commits), func(t *testing.T, repository Repository) {
+		latestTag, unreleased, err := Unreleased(repository, Module{Path: "unknown"}, DiffOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, latestTag)
+		assert.Empty(t, unreleased)
+	})
+}
+
+func TestUnreleased_ConfiguredModule(t *testing.T) {
+	var unreleased *Commit
+	forEachImplementation(t, unreleasedRepo(&unreleased), func(t *testing.T, repository Repository) {
+		module := NewConfiguredModule("lib", ModulePaths{Include: []string{"subdir"}})
+		latestTag, commits, err := Unreleased(repository, module, DiffOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, latestTag)
+		assert.Len(t, commits, 3)
+		assert.Equal(t, unreleased, commits[0])
+	})
+}
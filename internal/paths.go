@@ -0,0 +1,139 @@
+package monoreleaser
+
+import (
+	"path"
+	"strings"
+)
+
+// ModulePaths are the directories and files a Module consists of, if they differ from the Module directory.
+// They decouple the Commits of a Module from the name its Tags are prefixed with.
+// Besides directories, glob patterns select files anywhere in the repository, e.g. shared files like "proto/**" or "go.mod".
+// Patterns are matched like path.Match, except for "**", which matches any number of directories.
+type ModulePaths struct {
+	// Include are the directories and patterns, whose changes belong to the Module.
+	// If this option is not set, the Module directory will be used.
+	Include []string
+	// Exclude are directories and patterns inside of the included ones, whose changes do not belong to the Module.
+	Exclude []string
+}
+
+func (paths ModulePaths) isEmpty() bool {
+	return len(paths.Include) == 0 && len(paths.Exclude) == 0
+}
+
+// dirs returns the directories of the Module without trailing slashes, where the repository root is empty.
+func (paths ModulePaths) dirs(module string) ([]string, []string) {
+	include := paths.Include
+	if len(include) == 0 {
+		include = []string{module}
+	}
+
+	return cleanDirs(include), cleanDirs(paths.Exclude)
+}
+
+func cleanDirs(dirs []string) []string {
+	cleaned := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		dir = strings.TrimSuffix(dir, "/")
+		if dir == RootModule {
+			dir = ""
+		}
+		cleaned = append(cleaned, dir)
+	}
+	return cleaned
+}
+
+// filter returns whether a changed file belongs to the Module, or nil if every file does.
+func (paths ModulePaths) filter(module string) func(path string) bool {
+	if paths.isEmpty() && module == "" {
+		return nil
+	}
+
+	include, exclude := paths.dirs(module)
+	return func(path string) bool {
+		return matchesAny(path, include) && !matchesAny(path, exclude)
+	}
+}
+
+func matchesAny(file string, dirs []string) bool {
+	for _, dir := range dirs {
+		if isPattern(dir) {
+			if matchPattern(strings.Split(dir, "/"), strings.Split(file, "/")) {
+				return true
+			}
+			continue
+		}
+
+		if dir == "" || file == dir || strings.HasPrefix(file, modulePrefix(dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPattern reports whether a Module path is a glob pattern instead of a directory.
+func isPattern(dir string) bool {
+	return strings.ContainsAny(dir, "*?[")
+}
+
+// matchPattern matches the segments of a file path against the segments of a glob pattern.
+func matchPattern(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPattern(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	matched, err := path.Match(pattern[0], segments[0])
+	if err != nil || !matched {
+		return false
+	}
+	return matchPattern(pattern[1:], segments[1:])
+}
+
+// pathspecs returns the git pathspecs selecting the files of the Module, or nil if every file does.
+func (paths ModulePaths) pathspecs(module string) []string {
+	if paths.isEmpty() && module == "" {
+		return nil
+	}
+
+	include, exclude := paths.dirs(module)
+	pathspecs := make([]string, 0, len(include)+len(exclude))
+	for _, dir := range include {
+		pathspecs = append(pathspecs, pathspec(dir, ""))
+	}
+	for _, dir := range exclude {
+		pathspecs = append(pathspecs, pathspec(dir, "exclude"))
+	}
+
+	return pathspecs
+}
+
+// pathspec converts a directory, file or pattern into a git pathspec with the given magic.
+// Patterns use the glob magic, which matches "**" like matchPattern.
+// Other pathspecs match whole path components, so that they select a single file or a whole directory.
+func pathspec(dir string, magic string) string {
+	switch {
+	case isPattern(dir):
+		if magic != "" {
+			magic = "," + magic
+		}
+		return ":(glob" + magic + ")" + dir
+	case magic != "":
+		return ":(" + magic + ")" + dir
+	case dir == "":
+		return "."
+	default:
+		return dir
+	}
+}
@@ -0,0 +1,119 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModulePaths_Filter(t *testing.T) {
+	paths := ModulePaths{
+		Include: []string{"services/payments/api/", "libs/money"},
+		Exclude: []string{"services/payments/api/docs"},
+	}
+	filter := paths.filter("payments-api")
+	assert.True(t, filter("services/payments/api/main.go"))
+	assert.True(t, filter("libs/money/money.go"))
+	assert.False(t, filter("services/payments/api/docs/README.md"))
+	assert.False(t, filter("services/payments/apigateway/main.go"))
+	assert.False(t, filter("payments-api/main.go"))
+
+	filter = ModulePaths{}.filter("subdir")
+	assert.True(t, filter("subdir/main.go"))
+	assert.False(t, filter("main.go"))
+
+	assert.Nil(t, ModulePaths{}.filter(""))
+}
+
+func TestModulePaths_Filter_Patterns(t *testing.T) {
+	paths := ModulePaths{
+		Include: []string{"services/api", "proto/**", "go.mod", "libs/*/api.go"},
+		Exclude: []string{"**/*_test.go", "proto/internal/**"},
+	}
+	filter := paths.filter("api")
+	assert.True(t, filter("services/api/main.go"))
+	assert.True(t, filter("proto/payments.proto"))
+	assert.True(t, filter("proto/v1/payments.proto"))
+	assert.True(t, filter("go.mod"))
+	assert.True(t, filter("libs/auth/api.go"))
+	assert.False(t, filter("libs/auth/v2/api.go"))
+	assert.False(t, filter("services/api/main_test.go"))
+	assert.False(t, filter("proto/internal/health.proto"))
+	assert.False(t, filter("services/api.go.mod"))
+	assert.False(t, filter("services/web/go.mod"))
+	assert.False(t, filter("protocol/README.md"))
+}
+
+func TestModulePaths_Pathspecs(t *testing.T) {
+	paths := ModulePaths{
+		Include: []string{".", "libs/money/"},
+		Exclude: []string{"docs"},
+	}
+	assert.Equal(t, []string{".", "libs/money", ":(exclude)docs"}, paths.pathspecs("money"))
+	assert.Equal(t, []string{"subdir"}, ModulePaths{}.pathspecs("subdir"))
+
+	paths = ModulePaths{
+		Include: []string{"services/api", "proto/**", "go.mod"},
+		Exclude: []string{"**/*_test.go"},
+	}
+	assert.Equal(t, []string{"services/api", ":(glob)proto/**", "go.mod", ":(glob,exclude)**/*_test.go"}, paths.pathspecs("api"))
+	assert.Nil(t, ModulePaths{}.pathspecs(""))
+}
+
+func TestHistory_Paths(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		commitIter, err := repository.History(HistoryOptions{
+			Module: "unknown",
+			Paths:  ModulePaths{Include: []string{"."}, Exclude: []string{"subdir"}},
+		})
+		assert.NoError(t, err)
+
+		for i := lenCommits - 2; i > 0; i-- {
+			commit, err := commitIter.Next()
+			assert.NoError(t, err)
+			assert.Equal(t, commits[i], commit)
+		}
+
+		commit, err := commitIter.Next()
+		assert.ErrorIs(t, err, ErrEndOfHistory)
+		assert.Nil(t, commit)
+	})
+}
+
+func TestDiff_Paths(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits[lenCommits-1].Hash}
+		diffCommits, err := repository.Diff(newTag, nil, DiffOptions{
+			Module: "lib",
+			Paths:  ModulePaths{Include: []string{"subdir"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits[lenCommits-1], commits[0]}, diffCommits)
+	})
+}
+
+func TestTag_Paths(t *testing.T) {
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(false, &commits), func(t *testing.T, repository Repository) {
+		tag, err := repository.Tag("v1.0.0", TagOptions{
+			Module:    "subdir",
+			TagModule: "lib",
+			Paths:     ModulePaths{Include: []string{"."}, Exclude: []string{"subdir"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, commits[len(commits)-2].Hash, tag.Hash)
+		assert.Equal(t, "lib/v1.0.0", tag.Name)
+	})
+}
+
+func TestDiff_Paths_Patterns(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits[lenCommits-1].Hash}
+		diffCommits, err := repository.Diff(newTag, nil, DiffOptions{
+			Module: "lib",
+			Paths:  ModulePaths{Include: []string{"subdir", "1*"}, Exclude: []string{"**/0"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits[11], commits[10], commits[1]}, diffCommits)
+	})
+}
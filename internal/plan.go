@@ -0,0 +1,155 @@
+package monoreleaser
+
+import (
+	"fmt"
+)
+
+// A PlannedRelease is a Module with unreleased Changes and the version it is going to be released with.
+type PlannedRelease struct {
+	// A Module is just an application (directory) inside a mono repository.
+	Module string
+	// TagModule is the Module the Tags are prefixed with, if it differs from the Module, like TagOptions.TagModule.
+	TagModule string
+	// Name is the logical name of a configured Module, like Module.Name.
+	Name string
+	// Paths replace the Module directory as the filter of the Commits, like DiffOptions.Paths.
+	Paths ModulePaths
+	// LatestTag is the previous release of the Module, or nil if it has never been released.
+	LatestTag *Tag
+	// Version is the next version of the Module.
+	Version string
+	// Changes since the LatestTag.
+	Changes []Change
+	// DependencyBumps are the Changes caused by releases of Modules this Module depends on.
+	DependencyBumps []Change
+}
+
+// PlanReleases determines the next version of every Module with unreleased Changes.
+// Modules without Changes are skipped, the others keep the order of the given Modules.
+// Modules with VersionSourceFile are skipped as well, as they are planned by PlanFileReleases.
+func PlanReleases(repository Repository, modules []Module, opts DiffOptions) ([]PlannedRelease, error) {
+	var plan []PlannedRelease
+	for _, module := range modules {
+		if module.VersionSource == VersionSourceFile {
+			continue
+		}
+
+		latestTag, commits, err := Unreleased(repository, module, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ModuleName(module.Path), err)
+		}
+
+		var latestVersion string
+		if latestTag != nil {
+			latestVersion = latestTag.Version
+		}
+
+		changes := Extract(commits)
+		version, changed, err := NextVersion(latestVersion, changes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ModuleName(module.Path), err)
+		}
+		if !changed {
+			continue
+		}
+
+		plan = append(plan, PlannedRelease{
+			Module:    module.Path,
+			TagModule: module.TagModule,
+			Name:      module.Name,
+			Paths:     module.Paths,
+			LatestTag: latestTag,
+			Version:   version,
+			Changes:   changes,
+		})
+	}
+
+	return plan, nil
+}
+
+// CascadeReleases adds releases for all Modules depending on a released Module and notes the bumped dependencies.
+// Modules which would not have been released otherwise get a patch release.
+// The returned plan is ordered, so that dependencies are released before the Modules depending on them.
+func CascadeReleases(
+	repository Repository,
+	modules []Module,
+	plan []PlannedRelease,
+	graph DependencyGraph,
+) ([]PlannedRelease, error) {
+	order, err := graph.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	modulesByPath := make(map[string]Module, len(modules))
+	for _, module := range modules {
+		modulesByPath[module.Path] = module
+	}
+
+	planned := make(map[string]*PlannedRelease, len(plan))
+	for i := range plan {
+		planned[plan[i].Module] = &plan[i]
+	}
+
+	cascaded := make([]PlannedRelease, 0, len(order))
+	for _, module := range order {
+		var bumps []Change
+		for _, dependency := range graph[module] {
+			if release, ok := planned[dependency]; ok {
+				bumps = append(bumps, Change{
+					Message:  fmt.Sprintf("bumped dependency %s to %s", ModuleName(dependency), release.Version),
+					Semantic: Patch,
+				})
+			}
+		}
+
+		release, ok := planned[module]
+		if !ok && len(bumps) == 0 {
+			continue
+		}
+
+		// a planned release is at least a patch release already, so that only new releases need a version
+		if !ok {
+			dependent, found := modulesByPath[module]
+			if !found {
+				dependent = Module{Path: module}
+			}
+
+			latestTag, err := LatestTag(repository, dependent.tagModule())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ModuleName(module), err)
+			}
+
+			var latestVersion string
+			if latestTag != nil {
+				latestVersion = latestTag.Version
+			}
+			version, _, err := NextVersion(latestVersion, bumps)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ModuleName(module), err)
+			}
+
+			release = &PlannedRelease{
+				Module:    module,
+				TagModule: dependent.TagModule,
+				Name:      dependent.Name,
+				Paths:     dependent.Paths,
+				LatestTag: latestTag,
+				Version:   version,
+			}
+			planned[module] = release
+		}
+		release.DependencyBumps = bumps
+
+		cascaded = append(cascaded, *release)
+	}
+
+	// modules outside of the graph keep their order
+	for _, release := range plan {
+		if _, inGraph := graph[release.Module]; !inGraph {
+			cascaded = append(cascaded, release)
+		}
+	}
+
+	return cascaded, nil
+}
@@ -0,0 +1,81 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanReleases(t *testing.T) {
+	var unreleased *Commit
+	forEachImplementation(t, unreleasedRepo(&unreleased), func(t *testing.T, repository Repository) {
+		modules := []Module{{Path: ""}, {Path: "subdir"}, {Path: "unknown"}}
+		plan, err := PlanReleases(repository, modules, DiffOptions{})
+		require.NoError(t, err)
+		require.Len(t, plan, 2)
+
+		assert.Equal(t, "", plan[0].Module)
+		assert.Equal(t, "v1.10.0", plan[0].LatestTag.Name)
+		assert.Equal(t, "v1.11.0", plan[0].Version)
+		assert.Len(t, plan[0].Changes, 2)
+
+		assert.Equal(t, "subdir", plan[1].Module)
+		assert.Equal(t, "subdir/v1.11.0", plan[1].LatestTag.Name)
+		assert.Equal(t, "v1.11.1", plan[1].Version)
+		assert.Equal(t, []Change{{Message: unreleased.Message, Hash: unreleased.Hash, Semantic: Patch}}, plan[1].Changes)
+	})
+}
+
+func TestPlanReleases_NeverReleased(t *testing.T) {
+	var commits map[string]*Commit
+	forEachImplementation(t, mergeRepo(&commits), func(t *testing.T, repository Repository) {
+		plan, err := PlanReleases(repository, []Module{{Path: "subdir"}}, DiffOptions{})
+		require.NoError(t, err)
+		require.Len(t, plan, 1)
+		assert.Nil(t, plan[0].LatestTag)
+		assert.Equal(t, "v0.1.0", plan[0].Version)
+		assert.Len(t, plan[0].Changes, 4)
+	})
+}
+
+func TestCascadeReleases(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		plan := []PlannedRelease{
+			{Module: "subdir", Version: "v1.12.0", Changes: []Change{{Message: "feat: a", Semantic: Minor}}},
+			{Module: "", Version: "v1.11.0", Changes: []Change{{Message: "feat: b", Semantic: Minor}}},
+		}
+		graph := DependencyGraph{
+			"":             {"subdir"},
+			"subdir":       {},
+			"services/api": {""},
+			"services/web": {},
+		}
+
+		cascaded, err := CascadeReleases(repository, nil, plan, graph)
+		require.NoError(t, err)
+		require.Len(t, cascaded, 3)
+
+		assert.Equal(t, plan[0], cascaded[0])
+
+		assert.Equal(t, "", cascaded[1].Module)
+		assert.Equal(t, "v1.11.0", cascaded[1].Version)
+		assert.Equal(t, []Change{{Message: "bumped dependency subdir to v1.12.0", Semantic: Patch}}, cascaded[1].DependencyBumps)
+
+		assert.Equal(t, "services/api", cascaded[2].Module)
+		assert.Nil(t, cascaded[2].LatestTag)
+		assert.Equal(t, "v0.0.1", cascaded[2].Version)
+		assert.Empty(t, cascaded[2].Changes)
+		assert.Equal(t, []Change{{Message: "bumped dependency . to v1.11.0", Semantic: Patch}}, cascaded[2].DependencyBumps)
+	})
+}
+
+func TestCascadeReleases_Cycle(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		plan := []PlannedRelease{{Module: "subdir", Version: "v1.12.0"}}
+		graph := DependencyGraph{"subdir": {"other"}, "other": {"subdir"}}
+
+		_, err := CascadeReleases(repository, nil, plan, graph)
+		assert.ErrorIs(t, err, ErrDependencyCycle)
+	})
+}
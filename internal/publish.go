@@ -0,0 +1,98 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var (
+	ErrDraftNotFound    = errors.New("draft release not found")
+	ErrReleasePublished = errors.New("release has been published already")
+	ErrAssetsMissing    = errors.New("release assets are missing")
+)
+
+// A DraftRelease is a release at the provider, which is not visible to the public yet.
+type DraftRelease struct {
+	// ID of the release at the provider.
+	ID  int
+	Tag Tag
+	// Assets are the names of the files attached to the release.
+	Assets []string
+}
+
+// A ReleasePublisher publishes releases, which have been created as drafts (see ProviderOptions.Draft).
+type ReleasePublisher interface {
+	// Draft returns the DraftRelease of a Tag.
+	// It fails with ErrDraftNotFound, if there is no release of the Tag, and with ErrReleasePublished, if it is not a draft.
+	Draft(tag Tag) (*DraftRelease, error)
+	// PublishDraft publishes a DraftRelease, which becomes the latest release according to makeLatest.
+	PublishDraft(draft DraftRelease, makeLatest MakeLatest) error
+}
+
+// Optional parameters for publishing a release.
+type PublishOptions struct {
+	// Assets are the patterns (see path.Match) of the files, which have to be attached to the draft.
+	// Each pattern has to match at least one file, otherwise the draft is not published.
+	Assets []string
+	// MakeLatest decides whether the release becomes the latest release of the repository.
+	// If this option is not set, the provider decides.
+	MakeLatest MakeLatest
+}
+
+// Publish publishes the draft release of a version of a Module, which may be passed with or without its "v" prefix.
+// The Assets of the Module are required in addition to the ones of the options.
+func Publish(repository Repository, publisher ReleasePublisher, module Module, version string, opts PublishOptions) (*Tag, error) {
+	tags, err := repository.GetTags(GetTagOptions{Module: module.tagModule()})
+	if err != nil {
+		return nil, err
+	}
+	tags = ownTags(tags)
+
+	i := tagIndex(tags, version)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrTagNotFound, ModuleName(module.Path), version)
+	}
+	tag := tags[i]
+
+	draft, err := publisher.Draft(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	missing, err := MissingAssets(draft.Assets, append(module.Assets, opts.Assets...))
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("%w: %s has no %s", ErrAssetsMissing, tag.Name, strings.Join(missing, ", "))
+	}
+
+	if err := publisher.PublishDraft(*draft, opts.MakeLatest); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// MissingAssets returns the patterns (see path.Match), which match none of the assets.
+func MissingAssets(assets []string, patterns []string) ([]string, error) {
+	var missing []string
+	for _, pattern := range patterns {
+		found := false
+		for _, asset := range assets {
+			matched, err := path.Match(pattern, asset)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, pattern)
+			}
+			if matched {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, pattern)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,107 @@
+package monoreleaser
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// githubReleasesHandler lists the given releases and records the body of the PATCH request publishing one of them.
+func githubReleasesHandler(t *testing.T, releases string, patched *map[string]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/repos/kharf/myrepo/releases", r.URL.Path)
+			w.Write([]byte(releases))
+		case http.MethodPatch:
+			assert.Equal(t, "/repos/kharf/myrepo/releases/2", r.URL.Path)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(patched))
+			w.Write([]byte(`{"id": 2}`))
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}
+}
+
+const githubDrafts = `[
+  {"id": 1, "tag_name": "v1.10.0", "draft": false},
+  {"id": 2, "tag_name": "v1.9.0", "draft": true, "assets": [{"name": "app-linux-amd64.tar.gz"}, {"name": "checksums.txt"}]}
+]`
+
+func TestGithubReleaser_Draft(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	ts := redirectReleaser(t, releaser, githubReleasesHandler(t, githubDrafts, nil))
+	defer ts.Close()
+
+	draft, err := releaser.Draft(Tag{Name: "v1.9.0", Version: "v1.9.0"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, draft.ID)
+	assert.Equal(t, []string{"app-linux-amd64.tar.gz", "checksums.txt"}, draft.Assets)
+
+	_, err = releaser.Draft(Tag{Name: "v1.10.0", Version: "v1.10.0"})
+	assert.ErrorIs(t, err, ErrReleasePublished)
+
+	_, err = releaser.Draft(Tag{Name: "v1.8.0", Version: "v1.8.0"})
+	assert.ErrorIs(t, err, ErrDraftNotFound)
+}
+
+func TestPublish(t *testing.T) {
+	testCases := []struct {
+		name       string
+		version    string
+		assets     []string
+		makeLatest MakeLatest
+		expected   map[string]any
+		err        error
+	}{
+		{name: "without v", version: "1.9.0", expected: map[string]any{"draft": false}},
+		{
+			name:       "assets",
+			version:    "v1.9.0",
+			assets:     []string{"app-*.tar.gz", "checksums.txt"},
+			makeLatest: MakeLatestTrue,
+			expected:   map[string]any{"draft": false, "make_latest": "true"},
+		},
+		{name: "missing assets", version: "v1.9.0", assets: []string{"checksums.txt", "app.zip"}, err: ErrAssetsMissing},
+		{name: "published", version: "v1.10.0", err: ErrReleasePublished},
+		{name: "no tag", version: "v1.9.9", err: ErrTagNotFound},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+			var patched map[string]any
+			ts := redirectReleaser(t, releaser, githubReleasesHandler(t, githubDrafts, &patched))
+			defer ts.Close()
+
+			tag, err := Publish(
+				releaser.repository,
+				releaser,
+				Module{},
+				testCase.version,
+				PublishOptions{Assets: testCase.assets, MakeLatest: testCase.makeLatest},
+			)
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				assert.Nil(t, patched)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "v1.9.0", tag.Name)
+			assert.Equal(t, testCase.expected, patched)
+		})
+	}
+}
+
+func TestMissingAssets(t *testing.T) {
+	missing, err := MissingAssets([]string{"app-linux.tar.gz", "app-darwin.tar.gz"}, []string{"app-*.tar.gz", "app-*.zip", "sbom.json"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app-*.zip", "sbom.json"}, missing)
+
+	_, err = MissingAssets([]string{"app"}, []string{"["})
+	assert.Error(t, err)
+}
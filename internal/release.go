@@ -2,6 +2,7 @@ package monoreleaser
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,10 +15,13 @@ import (
 
 var (
 	ErrRequestUnsuccessful = errors.New("request was unsuccessful")
+	ErrRateLimited         = errors.New("rate limit exceeded")
+	ErrInvalidMakeLatest   = errors.New("invalid make latest")
 )
 
 // A file released alongside the changelog.
 type Artifact struct {
+	// Reader of the file content. Uploads of Readers, which are also io.Seekers, are retried after temporary failures.
 	Reader io.Reader
 	Name   string
 	Size   int64
@@ -27,19 +31,74 @@ type Artifact struct {
 type ReleaseOptions struct {
 	// A Module is just an application (directory) inside a mono repository.
 	Module string
+	// TagModule is the Module the Tag name is prefixed with, if it differs from the Module, like TagOptions.TagModule.
+	TagModule string
+	// Paths replace the Module directory as the filter of the Commits, like DiffOptions.Paths.
+	Paths ModulePaths
+	// VersionCommit is the Commit, which bumped the version files of the Module (see BumpVersionFiles).
+	// It is tagged instead of the latest Commit of the Module and left out of the changelog.
+	VersionCommit *Commit
 	// Artifacts to upload alongside the changelog.
 	Artifacts []Artifact
+	// When FirstParent is set, the changelog only contains the merge (PR) commits of the main line.
+	FirstParent bool
+	// Changes to add to the changelog, which are not part of the history, e.g. bumped dependencies.
+	Changes []Change
+	// VersionPolicy decides which versions are refused (see ValidateRelease).
+	// If this option is not set, VersionPolicyLenient will be used.
+	VersionPolicy VersionPolicy
+	// Provider options of the release, which are ignored by providers without releases.
+	Provider ProviderOptions
+}
+
+// Optional parameters for the release at the provider.
+type ProviderOptions struct {
+	// A Draft is not visible to the public until it is published, so that artifacts can be uploaded and verified before.
+	Draft bool
+	// When Prerelease is set, the release is marked as not ready for production.
+	// Releases of pre-release versions like v1.0.0-rc.1 are always marked.
+	Prerelease bool
+	// MakeLatest decides whether the release becomes the latest release of the repository.
+	// If this option is not set, the provider decides.
+	MakeLatest MakeLatest
+	// TargetCommitish is the branch or commit the Tag is created from by the provider, if it does not exist yet.
+	TargetCommitish string
+	// DiscussionCategoryName is the category of the discussion, which is started for the release.
+	DiscussionCategoryName string
+	// NameTemplate names the release. If this option is not set, the release is named like its Tag.
+	NameTemplate *ReleaseNameTemplate
+}
+
+// MakeLatest decides whether a release becomes the latest release of the repository.
+type MakeLatest string
+
+const (
+	MakeLatestTrue  MakeLatest = "true"
+	MakeLatestFalse MakeLatest = "false"
+	// MakeLatestLegacy makes the release with the highest version and latest creation date the latest release.
+	MakeLatestLegacy MakeLatest = "legacy"
+)
+
+// ParseMakeLatest returns the MakeLatest of the given name, where an empty name leaves the decision to the provider.
+func ParseMakeLatest(makeLatest string) (MakeLatest, error) {
+	switch MakeLatest(makeLatest) {
+	case "", MakeLatestTrue, MakeLatestFalse, MakeLatestLegacy:
+		return MakeLatest(makeLatest), nil
+	default:
+		return "", fmt.Errorf("%w: %s (expected true, false or legacy)", ErrInvalidMakeLatest, makeLatest)
+	}
 }
 
 // A Releaser is capable of drafting and tagging of release versions and posting changelogs to external sources like scms.
 type Releaser interface {
 	// Release creates a given version with specified optional options like module name.
+	// Implementations refuse versions ValidateRelease fails for, before anything is tagged.
 	// It is also able to create Changelogs and depending on the implementation it posts/uploads it on external sources like scms (github, gitlab ...) and/or writes it to a Changelog file.
 	Release(version string, opts ReleaseOptions) error
 }
 
 type GithubClient struct {
-	client http.Client
+	client HTTPClient
 	url    *url.URL
 	header http.Header
 }
@@ -54,6 +113,29 @@ type GithubReleaser struct {
 	repository    Repository
 	releaseClient GithubClient
 	assetClient   GithubClient
+	// ctx cancels the requests, or is nil if they are not cancelled.
+	ctx context.Context
+}
+
+// WithContext returns a copy of the releaser, whose requests are cancelled by the context.
+func (rel GithubReleaser) WithContext(ctx context.Context) *GithubReleaser {
+	rel.ctx = ctx
+	return &rel
+}
+
+// WithRetryPolicy returns a copy of the releaser, whose requests are retried according to the RetryPolicy.
+// Releasers use DefaultRetryPolicy, if it is not changed.
+func (rel GithubReleaser) WithRetryPolicy(policy RetryPolicy) *GithubReleaser {
+	rel.releaseClient.client.policy = policy
+	rel.assetClient.client.policy = policy
+	return &rel
+}
+
+func (rel GithubReleaser) context() context.Context {
+	if rel.ctx == nil {
+		return context.Background()
+	}
+	return rel.ctx
 }
 
 func (rel GithubReleaser) ReleaseClient() GithubClient {
@@ -65,6 +147,8 @@ func (rel GithubReleaser) AssetClient() GithubClient {
 }
 
 var _ Releaser = GithubReleaser{}
+var _ ReleaseEditor = GithubReleaser{}
+var _ ReleaseBackfiller = GithubReleaser{}
 
 // User specific static releaser settings.
 type UserSettings struct {
@@ -77,7 +161,7 @@ func NewGithubReleaser(
 	timeout int,
 	userSettings UserSettings,
 ) (*GithubReleaser, error) {
-	releaseClient := http.Client{Timeout: time.Second * 10}
+	releaseClient := &http.Client{Timeout: time.Second * 10}
 	releaseHeader := http.Header{}
 	releaseHeader.Add("Accept", "application/vnd.github+json")
 	releaseHeader.Add("Authorization", "Bearer "+userSettings.Token)
@@ -89,7 +173,7 @@ func NewGithubReleaser(
 		return nil, err
 	}
 
-	assetClient := http.Client{Timeout: time.Second * time.Duration(timeout)}
+	assetClient := &http.Client{Timeout: time.Second * time.Duration(timeout)}
 	assetHeader := http.Header{}
 	assetHeader.Add("Accept", "application/vnd.github+json")
 	assetHeader.Add("Content-Type", "application/octet-stream")
@@ -105,12 +189,12 @@ func NewGithubReleaser(
 	return &GithubReleaser{
 		repository: repository,
 		releaseClient: GithubClient{
-			client: releaseClient,
+			client: NewHTTPClient(releaseClient, DefaultRetryPolicy),
 			url:    releaseURL,
 			header: releaseHeader,
 		},
 		assetClient: GithubClient{
-			client: assetClient,
+			client: NewHTTPClient(assetClient, DefaultRetryPolicy),
 			url:    assetURL,
 			header: assetHeader,
 		},
@@ -119,27 +203,35 @@ func NewGithubReleaser(
 
 func (rel GithubReleaser) Release(version string, opts ReleaseOptions) error {
 	monoRepo := rel.repository
-	tags, err := monoRepo.GetTags(GetTagOptions{Module: opts.Module})
-	if err != nil {
+	if err := ValidateRelease(monoRepo, version, opts); err != nil {
 		return err
 	}
 
-	tag, err := monoRepo.Tag(version, TagOptions{Module: opts.Module})
+	// looked up before tagging, as the new Tag would be the latest
+	latestTag, err := LatestTag(monoRepo, resolveTagModule(opts.Module, opts.TagModule))
 	if err != nil {
 		return err
 	}
 
-	var latestTag *Tag
-	if len(tags) > 0 {
-		latestTag = &tags[0]
+	tagOpts := TagOptions{Module: opts.Module, TagModule: opts.TagModule, Paths: opts.Paths}
+	if opts.VersionCommit != nil {
+		tagOpts.Hash = opts.VersionCommit.Hash
+	}
+	tag, err := monoRepo.Tag(version, tagOpts)
+	if err != nil {
+		return err
 	}
 
-	diffs, err := monoRepo.Diff(*tag, latestTag, DiffOptions{Module: opts.Module})
+	diffs, err := monoRepo.Diff(*tag, latestTag, DiffOptions{Module: opts.Module, Paths: opts.Paths, FirstParent: opts.FirstParent})
 	if err != nil {
 		return err
 	}
 
-	cl, err := GenerateChangelog(Extract(diffs))
+	if opts.VersionCommit != nil {
+		diffs = withoutCommit(diffs, opts.VersionCommit.Hash)
+	}
+
+	cl, err := GenerateChangelog(append(Extract(diffs), opts.Changes...))
 	if err != nil {
 		return err
 	}
@@ -152,44 +244,281 @@ func (rel GithubReleaser) Release(version string, opts ReleaseOptions) error {
 	return nil
 }
 
+// ValidateRelease validates the version a Module is going to be released with against its Tags
+// and, following VersionPolicyStrict, against the Changes since its latest Tag.
+// The errors are ErrInvalidVersion, ErrVersionExists, ErrVersionDowngrade and ErrVersionSkipped.
+func ValidateRelease(repository Repository, version string, opts ReleaseOptions) error {
+	tags, err := repository.GetTags(GetTagOptions{Module: resolveTagModule(opts.Module, opts.TagModule)})
+	if err != nil {
+		return err
+	}
+	tags = ownTags(tags)
+
+	var changes []Change
+	if opts.VersionPolicy == VersionPolicyStrict && len(tags) > 0 {
+		var hash string
+		if opts.VersionCommit != nil {
+			hash = opts.VersionCommit.Hash
+		} else {
+			hash, err = repository.Head()
+			if err != nil {
+				return err
+			}
+		}
+
+		commits, err := repository.Diff(Tag{Hash: hash}, &tags[0], DiffOptions{Module: opts.Module, Paths: opts.Paths, FirstParent: opts.FirstParent})
+		if err != nil {
+			return err
+		}
+		if opts.VersionCommit != nil {
+			commits = withoutCommit(commits, opts.VersionCommit.Hash)
+		}
+		changes = append(Extract(commits), opts.Changes...)
+	}
+
+	return ValidateVersion(tags, version, changes, opts.VersionPolicy)
+}
+
+func withoutCommit(commits []*Commit, hash string) []*Commit {
+	remaining := make([]*Commit, 0, len(commits))
+	for _, commit := range commits {
+		if commit.Hash != hash {
+			remaining = append(remaining, commit)
+		}
+	}
+	return remaining
+}
+
 type githubResponse struct {
-	ID int `json:"id"`
+	ID      int    `json:"id"`
+	Body    string `json:"body"`
+	TagName string `json:"tag_name"`
+	Draft   bool   `json:"draft"`
+	Assets  []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
 }
 
-func (rel GithubReleaser) post(tag Tag, changelog Changelog, opts ReleaseOptions) error {
-	body, err := json.Marshal(map[string]string{
-		"tag_name": tag.Name,
-		"body":     string(changelog),
-		"name":     tag.Name,
-	})
+// Changelog returns the body of the GitHub release of a Tag.
+func (rel GithubReleaser) Changelog(tag Tag) (Changelog, error) {
+	release, err := rel.getRelease(tag)
+	if err != nil {
+		return "", err
+	}
+	return Changelog(release.Body), nil
+}
+
+// EditChangelog replaces the body of the GitHub release of a Tag.
+func (rel GithubReleaser) EditChangelog(tag Tag, changelog Changelog) error {
+	release, err := rel.getRelease(tag)
 	if err != nil {
 		return err
 	}
 
-	request, err := http.NewRequest(
-		http.MethodPost,
-		rel.releaseClient.url.String(),
-		bytes.NewBuffer(body),
-	)
+	body, err := json.Marshal(map[string]string{"body": string(changelog)})
 	if err != nil {
 		return err
 	}
 
-	request.Header = rel.releaseClient.header
+	_, err = rel.send(http.MethodPatch, rel.releaseClient.url.String()+"/"+strconv.Itoa(release.ID), bytes.NewBuffer(body))
+	return err
+}
+
+// githubPageSize is the maximum number of releases GitHub returns per page.
+const githubPageSize = 100
 
-	response, err := rel.releaseClient.client.Do(request)
+// ReleasedTags returns the names of the Tags of all GitHub releases, including drafts.
+func (rel GithubReleaser) ReleasedTags() (map[string]bool, error) {
+	releases, err := rel.releases()
+	if err != nil {
+		return nil, err
+	}
+
+	released := make(map[string]bool, len(releases))
+	for _, release := range releases {
+		released[release.TagName] = true
+	}
+	return released, nil
+}
+
+// releases lists all GitHub releases including drafts, page by page.
+func (rel GithubReleaser) releases() ([]githubResponse, error) {
+	var releases []githubResponse
+	for page := 1; ; page++ {
+		responseBody, err := rel.send(
+			http.MethodGet,
+			fmt.Sprintf("%s?per_page=%d&page=%d", rel.releaseClient.url.String(), githubPageSize, page),
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageReleases []githubResponse
+		if err := json.Unmarshal(responseBody, &pageReleases); err != nil {
+			return nil, err
+		}
+		releases = append(releases, pageReleases...)
+
+		if len(pageReleases) < githubPageSize {
+			return releases, nil
+		}
+	}
+}
+
+var _ ReleasePublisher = GithubReleaser{}
+
+// Draft looks up the draft GitHub release of a Tag.
+// Drafts are only part of the list of releases, as looking them up by their Tag name is not supported by GitHub.
+func (rel GithubReleaser) Draft(tag Tag) (*DraftRelease, error) {
+	releases, err := rel.releases()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, release := range releases {
+		if release.TagName != tag.Name {
+			continue
+		}
+		if !release.Draft {
+			return nil, fmt.Errorf("%w: %s", ErrReleasePublished, tag.Name)
+		}
+
+		draft := DraftRelease{ID: release.ID, Tag: tag}
+		for _, asset := range release.Assets {
+			draft.Assets = append(draft.Assets, asset.Name)
+		}
+		return &draft, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrDraftNotFound, tag.Name)
+}
+
+// PublishDraft publishes a draft GitHub release.
+func (rel GithubReleaser) PublishDraft(draft DraftRelease, makeLatest MakeLatest) error {
+	release := map[string]any{"draft": false}
+	if makeLatest != "" {
+		release["make_latest"] = string(makeLatest)
+	}
+
+	body, err := json.Marshal(release)
 	if err != nil {
 		return err
 	}
+
+	_, err = rel.send(http.MethodPatch, rel.releaseClient.url.String()+"/"+strconv.Itoa(draft.ID), bytes.NewBuffer(body))
+	return err
+}
+
+// CreateRelease creates a GitHub release for an existing Tag.
+func (rel GithubReleaser) CreateRelease(tag Tag, changelog Changelog, opts ReleaseOptions) error {
+	return rel.post(tag, changelog, ReleaseOptions{Module: opts.Module, TagModule: opts.TagModule, Provider: opts.Provider})
+}
+
+// getRelease looks up the GitHub release of a Tag.
+func (rel GithubReleaser) getRelease(tag Tag) (*githubResponse, error) {
+	responseBody, err := rel.send(http.MethodGet, rel.releaseClient.url.String()+"/tags/"+url.PathEscape(tag.Name), nil)
+	var githubErr *GithubError
+	if errors.As(err, &githubErr) && githubErr.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, tag.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var release githubResponse
+	if err := json.Unmarshal(responseBody, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// A GithubError is an unsuccessful response of the GitHub API.
+type GithubError struct {
+	StatusCode int
+	Body       []byte
+}
+
+var _ error = &GithubError{}
+
+func (err *GithubError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrRequestUnsuccessful, err.Body)
+}
+
+func (err *GithubError) Is(target error) bool {
+	return target == ErrRequestUnsuccessful
+}
+
+// A RateLimitError is returned, when the GitHub API refuses requests until the rate limit resets.
+type RateLimitError struct {
+	// Reset is the time the rate limit resets, or the zero time if GitHub did not tell.
+	Reset time.Time
+}
+
+var _ error = &RateLimitError{}
+
+func (err *RateLimitError) Error() string {
+	if err.Reset.IsZero() {
+		return ErrRateLimited.Error()
+	}
+	return fmt.Sprintf("%s until %s", ErrRateLimited, err.Reset.UTC().Format(time.RFC3339))
+}
+
+func (err *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// send sends a request to the GitHub releases API and returns the response body.
+func (rel GithubReleaser) send(method string, url string, body io.Reader) ([]byte, error) {
+	request, err := http.NewRequestWithContext(rel.context(), method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	return rel.releaseClient.do(request)
+}
+
+// do sends a request to the GitHub API and returns the response body.
+// Unsuccessful responses are returned as GithubError, or RateLimitError if the rate limit has been exceeded.
+// The PATCH requests only replace fields of a release, so that they are retried like GET requests.
+// POST requests are only retried, when they have been rate limited, as GitHub creates drafts of the same Tag more than once.
+func (client GithubClient) do(request *http.Request) ([]byte, error) {
+	request.Header = client.header.Clone()
+
+	response, err := client.client.Do(request, RequestOptions{Idempotent: request.Method == http.MethodPatch})
+	if err != nil {
+		return nil, err
+	}
 	defer response.Body.Close()
 
 	responseBody, err := io.ReadAll(response.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	if reset, limited := rateLimitReset(response, time.Now()); limited {
+		return nil, &RateLimitError{Reset: reset}
+	}
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		return fmt.Errorf("%w: %s", ErrRequestUnsuccessful, responseBody)
+		return nil, &GithubError{StatusCode: response.StatusCode, Body: responseBody}
+	}
+
+	return responseBody, nil
+}
+
+func (rel GithubReleaser) post(tag Tag, changelog Changelog, opts ReleaseOptions) error {
+	release, err := githubRelease(tag, changelog, opts)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(release)
+	if err != nil {
+		return err
+	}
+
+	responseBody, err := rel.send(http.MethodPost, rel.releaseClient.url.String(), bytes.NewBuffer(body))
+	if err != nil {
+		return err
 	}
 
 	var ghResponse githubResponse
@@ -206,26 +535,76 @@ func (rel GithubReleaser) post(tag Tag, changelog Changelog, opts ReleaseOptions
 	return nil
 }
 
+// githubRelease returns the request body creating the release of a Tag, which only contains the options that are set.
+func githubRelease(tag Tag, changelog Changelog, opts ReleaseOptions) (map[string]any, error) {
+	provider := opts.Provider
+	name := tag.Name
+	if provider.NameTemplate != nil {
+		var err error
+		name, err = provider.NameTemplate.Name(tag, resolveTagModule(opts.Module, opts.TagModule))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	release := map[string]any{
+		"tag_name": tag.Name,
+		"body":     string(changelog),
+		"name":     name,
+	}
+	if provider.Draft {
+		release["draft"] = true
+	}
+	if provider.Prerelease || IsPrerelease(tag.Version) {
+		release["prerelease"] = true
+	}
+	if provider.MakeLatest != "" {
+		release["make_latest"] = string(provider.MakeLatest)
+	}
+	if provider.TargetCommitish != "" {
+		release["target_commitish"] = provider.TargetCommitish
+	}
+	if provider.DiscussionCategoryName != "" {
+		release["discussion_category_name"] = provider.DiscussionCategoryName
+	}
+
+	return release, nil
+}
+
 func (rel GithubReleaser) upload(releaseID int, opts ReleaseOptions) error {
 	for _, artifact := range opts.Artifacts {
-		request, err := http.NewRequest(
+		body := artifact.Reader
+		seeker, seekable := artifact.Reader.(io.Seeker)
+		if seekable {
+			// the transport closes the body after every attempt, which must not close files read again by a retry
+			body = io.NopCloser(artifact.Reader)
+		}
+
+		request, err := http.NewRequestWithContext(
+			rel.context(),
 			http.MethodPost,
 			rel.assetClient.url.String()+"/"+strconv.Itoa(releaseID)+"/assets?name="+artifact.Name,
-			artifact.Reader,
+			body,
 		)
 		if err != nil {
 			return err
 		}
 
-		request.Header = rel.assetClient.header
-
 		request.ContentLength = artifact.Size
+		// artifacts, which can be read again, are retried
+		if seekable && request.GetBody == nil {
+			reader := artifact.Reader
+			request.GetBody = func() (io.ReadCloser, error) {
+				if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return io.NopCloser(reader), nil
+			}
+		}
 
-		response, err := rel.assetClient.client.Do(request)
-		if err != nil {
-			return err
+		if _, err := rel.assetClient.do(request); err != nil {
+			return fmt.Errorf("%s: %w", artifact.Name, err)
 		}
-		defer response.Body.Close()
 	}
 
 	return nil
@@ -2,12 +2,15 @@ package monoreleaser
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -19,11 +22,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// testRetryPolicy retries without noticeable delays and refuses to wait for rate limits, which reset after more than a second.
+var testRetryPolicy = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxWait: time.Second}
+
 func createRepoAndGithubReleaser(
 	t *testing.T,
 	userSettings UserSettings,
 ) ([]*Commit, *GithubReleaser) {
-	repository, commits, _, _ := newRepo(false)
+	repository, commits, _, _ := newRepo(t.TempDir(), false)
 
 	workTree, err := repository.repository.Worktree()
 	if err != nil {
@@ -50,6 +56,7 @@ func createRepoAndGithubReleaser(
 	commits = append(commits, &Commit{Hash: lastCommitHash.String(), Message: message})
 
 	releaser, _ := NewGithubReleaser("kharf", repository, 10, userSettings)
+	releaser = releaser.WithRetryPolicy(testRetryPolicy)
 
 	expectedUrl, _ := url.Parse("https://api.github.com/repos/kharf/myrepo/releases")
 	assert.Equal(t, expectedUrl.String(), releaser.ReleaseClient().URL().String())
@@ -60,6 +67,12 @@ func createRepoAndGithubReleaser(
 	return commits, releaser
 }
 
+// unreleasedCommits returns the Commits of createRepoAndGithubReleaser since the latest Tag of the root Module, newest first.
+// The Commit before the release change is only tagged for the nested Module subdir.
+func unreleasedCommits(commits []*Commit) []*Commit {
+	return []*Commit{commits[len(commits)-1], commits[len(commits)-2]}
+}
+
 func createServer(t *testing.T, changelog Changelog, releaser *GithubReleaser) *httptest.Server {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		actualHeader := r.Header
@@ -127,9 +140,9 @@ func createServer(t *testing.T, changelog Changelog, releaser *GithubReleaser) *
 			actualBody, err := io.ReadAll(r.Body)
 			assert.NoError(t, err)
 			expectedBody, _ := json.Marshal(map[string]string{
-				"tag_name": "v1",
+				"tag_name": "v2",
 				"body":     string(changelog),
-				"name":     "v1",
+				"name":     "v2",
 			})
 
 			assert.Equal(t, expectedBody, actualBody)
@@ -228,39 +241,67 @@ func createServer(t *testing.T, changelog Changelog, releaser *GithubReleaser) *
 
 func TestGithubReleaser_Release(t *testing.T) {
 	commits, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
-	diffs := []*Commit{commits[len(commits)-1]}
+	diffs := unreleasedCommits(commits)
 	changes := Extract(diffs)
 	changelog, _ := GenerateChangelog(changes)
 
 	ts := createServer(t, changelog, releaser)
 	defer ts.Close()
 
-	err := releaser.Release("v1", ReleaseOptions{})
+	err := releaser.Release("v2", ReleaseOptions{})
+	assert.NoError(t, err)
+}
+
+func TestGithubReleaser_Release_NestedModuleTag(t *testing.T) {
+	commits, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	// the highest Tag listed for the root Module belongs to the nested Module
+	_, err := releaser.repository.Tag("v9.0.0", TagOptions{Module: "subdir", Hash: commits[0].Hash})
+	require.NoError(t, err)
+
+	changelog, _ := GenerateChangelog(Extract(unreleasedCommits(commits)))
+	ts := createServer(t, changelog, releaser)
+	defer ts.Close()
+
+	err = releaser.Release("v2", ReleaseOptions{})
+	assert.NoError(t, err)
+}
+
+func TestGithubReleaser_Release_Changes(t *testing.T) {
+	commits, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	bump := Change{Message: "bumped dependency libs/auth to v1.4.0", Semantic: Patch}
+	changes := append(Extract(unreleasedCommits(commits)), bump)
+	changelog, _ := GenerateChangelog(changes)
+	assert.Contains(t, string(changelog), "- bumped dependency libs/auth to v1.4.0")
+
+	ts := createServer(t, changelog, releaser)
+	defer ts.Close()
+
+	err := releaser.Release("v2", ReleaseOptions{Changes: []Change{bump}})
 	assert.NoError(t, err)
 }
 
 func TestGithubReleaser_Release_NoToken(t *testing.T) {
 	commits, releaser := createRepoAndGithubReleaser(t, UserSettings{})
-	diffs := []*Commit{commits[len(commits)-1]}
+	diffs := unreleasedCommits(commits)
 	changes := Extract(diffs)
 	changelog, _ := GenerateChangelog(changes)
 
 	ts := createServer(t, changelog, releaser)
 	defer ts.Close()
 
-	err := releaser.Release("v1", ReleaseOptions{})
+	err := releaser.Release("v2", ReleaseOptions{})
 	assert.ErrorIs(t, err, ErrRequestUnsuccessful)
 }
 
 func TestGithubReleaser_Release_NoCommitHistory(t *testing.T) {
 	_, releaser := createRepoAndGithubReleaser(t, UserSettings{})
-	err := releaser.Release("v1", ReleaseOptions{Module: "notexisting"})
+	err := releaser.Release("v2", ReleaseOptions{Module: "notexisting"})
 	assert.ErrorIs(t, err, ErrEndOfHistory)
 }
 
 func TestGithubReleaser_Release_Upload(t *testing.T) {
 	commits, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
-	diffs := []*Commit{commits[len(commits)-1]}
+	diffs := unreleasedCommits(commits)
 	changes := Extract(diffs)
 	changelog, _ := GenerateChangelog(changes)
 
@@ -281,6 +322,251 @@ func TestGithubReleaser_Release_Upload(t *testing.T) {
 		},
 	}
 
-	err := releaser.Release("v1", ReleaseOptions{Artifacts: artifacts})
+	err := releaser.Release("v2", ReleaseOptions{Artifacts: artifacts})
+	assert.NoError(t, err)
+}
+
+func TestGithubReleaser_Release_VersionCommit(t *testing.T) {
+	commits, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	changes := Extract(unreleasedCommits(commits))
+	changelog, _ := GenerateChangelog(changes)
+
+	gitRepository := releaser.repository.(GoGitRepository).repository
+	config, err := gitRepository.Config()
+	require.NoError(t, err)
+	config.User.Name = "orca"
+	config.User.Email = "orca-dev@mail.com"
+	require.NoError(t, gitRepository.SetConfig(config))
+
+	workTree, err := gitRepository.Worktree()
+	require.NoError(t, err)
+	file, err := workTree.Filesystem.Create("VERSION")
+	require.NoError(t, err)
+	file.Close()
+	versionCommit, err := releaser.repository.Commit("chore(release): . v2", CommitOptions{Paths: []string{"VERSION"}})
+	require.NoError(t, err)
+
+	ts := createServer(t, changelog, releaser)
+	defer ts.Close()
+
+	err = releaser.Release("v2", ReleaseOptions{VersionCommit: versionCommit})
+	assert.NoError(t, err)
+
+	tag, err := releaser.repository.GetTag("v2", GetTagOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, versionCommit.Hash, tag.Hash)
+}
+
+func TestValidateRelease(t *testing.T) {
+	var unreleased *Commit
+	forEachImplementation(t, unreleasedRepo(&unreleased), func(t *testing.T, repository Repository) {
+		strict := ReleaseOptions{Module: "subdir", VersionPolicy: VersionPolicyStrict}
+		assert.NoError(t, ValidateRelease(repository, "v1.11.1", strict))
+		assert.ErrorIs(t, ValidateRelease(repository, "v1.12.0", strict), ErrVersionSkipped)
+		assert.ErrorIs(t, ValidateRelease(repository, "v1.11.0", strict), ErrVersionExists)
+
+		// tags of nested Modules are ignored by the root Module
+		assert.NoError(t, ValidateRelease(repository, "v1.10.1", ReleaseOptions{}))
+		assert.NoError(t, ValidateRelease(repository, "v2.0.0", ReleaseOptions{Module: "subdir"}))
+	})
+}
+
+func TestGithubReleaser_Release_Downgrade(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+
+	err := releaser.Release("v0.1.0", ReleaseOptions{})
+	assert.ErrorIs(t, err, ErrVersionDowngrade)
+
+	_, err = releaser.repository.GetTag("v0.1.0", GetTagOptions{})
+	assert.ErrorIs(t, err, ErrTagNotFound)
+}
+
+func TestGithubReleaser_Release_ProviderOptions(t *testing.T) {
+	nameTemplate, err := NewReleaseNameTemplate("{{if .Module}}{{.Module}} {{end}}{{.Version}}")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		version  string
+		opts     ReleaseOptions
+		provider ProviderOptions
+		expected map[string]any
+	}{
+		{
+			name:     "defaults",
+			version:  "v2",
+			expected: map[string]any{"tag_name": "v2", "name": "v2"},
+		},
+		{
+			name:    "draft",
+			version: "v2",
+			provider: ProviderOptions{
+				Draft:                  true,
+				MakeLatest:             MakeLatestFalse,
+				TargetCommitish:        "main",
+				DiscussionCategoryName: "Announcements",
+				NameTemplate:           nameTemplate,
+			},
+			expected: map[string]any{
+				"tag_name":                 "v2",
+				"name":                     "2",
+				"draft":                    true,
+				"make_latest":              "false",
+				"target_commitish":         "main",
+				"discussion_category_name": "Announcements",
+			},
+		},
+		{
+			name:     "configured module",
+			version:  "v2.0.0",
+			opts:     ReleaseOptions{Module: "subdir", TagModule: "payments-api"},
+			provider: ProviderOptions{NameTemplate: nameTemplate},
+			expected: map[string]any{"tag_name": "payments-api/v2.0.0", "name": "payments-api 2.0.0"},
+		},
+		{
+			name:     "prerelease version",
+			version:  "v2.0.0-rc.1",
+			expected: map[string]any{"tag_name": "v2.0.0-rc.1", "name": "v2.0.0-rc.1", "prerelease": true},
+		},
+		{
+			name:     "prerelease",
+			version:  "v2",
+			provider: ProviderOptions{Prerelease: true},
+			expected: map[string]any{"tag_name": "v2", "name": "v2", "prerelease": true},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+			ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+
+				var release map[string]any
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&release))
+				delete(release, "body")
+				assert.Equal(t, testCase.expected, release)
+
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id": 1}`))
+			})
+			defer ts.Close()
+
+			opts := testCase.opts
+			opts.Provider = testCase.provider
+			err := releaser.Release(testCase.version, opts)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGithubReleaser_Release_Retries(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	failures := map[string]int{"/repos/kharf/myrepo/releases": 2, "/repos/kharf/myrepo/releases/1/assets": 1}
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		if failures[r.URL.Path] > 0 {
+			failures[r.URL.Path]--
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/assets") {
+			// the artifact is uploaded again from the start
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, "file content", string(body))
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	})
+	defer ts.Close()
+
+	file := []byte("file content")
+	artifacts := []Artifact{{Name: "monoreleaser", Reader: bytes.NewReader(file), Size: int64(len(file))}}
+	err := releaser.Release("v2", ReleaseOptions{Artifacts: artifacts})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"/repos/kharf/myrepo/releases": 0, "/repos/kharf/myrepo/releases/1/assets": 0}, failures)
+}
+
+func TestGithubReleaser_Release_RetriesFile(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	var uploads int
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/assets") {
+			uploads++
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, "file content", string(body))
+			if uploads == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	})
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "monoreleaser")
+	require.NoError(t, os.WriteFile(path, []byte("file content"), 0o644))
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	err = releaser.Release("v2", ReleaseOptions{Artifacts: []Artifact{{Name: "monoreleaser", Reader: file, Size: 12}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, uploads)
+}
+
+func TestGithubReleaser_Release_ServerErrorNotRetried(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	var posts int
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer ts.Close()
+
+	// the release may have been created, so that it is not created again
+	err := releaser.Release("v2", ReleaseOptions{Provider: ProviderOptions{Draft: true}})
+	assert.ErrorIs(t, err, ErrRequestUnsuccessful)
+	assert.Equal(t, 1, posts)
+}
+
+func TestGithubReleaser_PublishDraft_Retries(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	var patches int
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		patches++
+		if patches == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"id": 2}`))
+	})
+	defer ts.Close()
+
+	err := releaser.PublishDraft(DraftRelease{ID: 2}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, patches)
+}
+
+func TestGithubReleaser_WithContext(t *testing.T) {
+	_, releaser := createRepoAndGithubReleaser(t, UserSettings{Token: "abcd"})
+	ts := redirectReleaser(t, releaser, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := releaser.WithContext(ctx).ReleasedTags()
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = releaser.ReleasedTags()
 	assert.NoError(t, err)
 }
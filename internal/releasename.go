@@ -0,0 +1,50 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+var ErrInvalidReleaseName = errors.New("invalid release name template")
+
+// A ReleaseNameTemplate names releases at the provider by a text/template, e.g. "{{.Module}} {{.Version}}".
+// The template is executed with the name of the Tag, the Module and the Version, whose leading "v" is stripped.
+// The Module is the one the Tags are prefixed with, e.g. the name of a configured Module instead of its directory.
+// The Module of the repository root is empty like in a TagFormat.
+type ReleaseNameTemplate struct {
+	template *template.Template
+}
+
+type releaseNameData struct {
+	Tag     string
+	Module  string
+	Version string
+}
+
+// NewReleaseNameTemplate parses a release name template.
+func NewReleaseNameTemplate(text string) (*ReleaseNameTemplate, error) {
+	tmpl, err := template.New("release").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReleaseName, err)
+	}
+
+	nameTemplate := &ReleaseNameTemplate{template: tmpl}
+	// fail early on references to unknown fields
+	if _, err := nameTemplate.Name(Tag{Name: "module/v1.0.0", Version: "v1.0.0"}, "module"); err != nil {
+		return nil, err
+	}
+
+	return nameTemplate, nil
+}
+
+// Name returns the name of the release of a Tag of a Module.
+func (nameTemplate ReleaseNameTemplate) Name(tag Tag, module string) (string, error) {
+	var sb strings.Builder
+	data := releaseNameData{Tag: tag.Name, Module: module, Version: strings.TrimPrefix(tag.Version, "v")}
+	if err := nameTemplate.template.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidReleaseName, err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,41 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseNameTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		module   string
+		tag      Tag
+		expected string
+	}{
+		{name: "module and version", template: "{{.Module}} {{.Version}}", module: "api", tag: Tag{Name: "api/v1.2.3", Version: "v1.2.3"}, expected: "api 1.2.3"},
+		{name: "tag", template: "Release {{.Tag}}", module: "api", tag: Tag{Name: "api/v1.2.3", Version: "v1.2.3"}, expected: "Release api/v1.2.3"},
+		{name: "root", template: "{{if .Module}}{{.Module}} {{end}}v{{.Version}}", module: "", tag: Tag{Name: "v1.2.3", Version: "v1.2.3"}, expected: "v1.2.3"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			nameTemplate, err := NewReleaseNameTemplate(testCase.template)
+			require.NoError(t, err)
+
+			name, err := nameTemplate.Name(testCase.tag, testCase.module)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, name)
+		})
+	}
+}
+
+func TestNewReleaseNameTemplate_Invalid(t *testing.T) {
+	for _, text := range []string{"{{.Module", "{{.Unknown}}"} {
+		_, err := NewReleaseNameTemplate(text)
+		assert.ErrorIs(t, err, ErrInvalidReleaseName, text)
+	}
+}
@@ -3,12 +3,18 @@ package monoreleaser
 import (
 	"errors"
 	"io"
+	"path"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/go-git/go-billy/v5/util"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/mod/semver"
 )
 
 // Iterator is an object that enables to traverse lists lazily.
@@ -17,8 +23,10 @@ type Iter[T any] interface {
 }
 
 // GenericIter traverses lists by the provided NextFunc.
+// The optional CloseFunc releases the resources held by the traversal.
 type GenericIter[T any] struct {
-	NextFunc func() (T, error)
+	NextFunc  func() (T, error)
+	CloseFunc func()
 }
 
 var _ Iter[any] = GenericIter[any]{}
@@ -27,6 +35,13 @@ func (iter GenericIter[T]) Next() (T, error) {
 	return iter.NextFunc()
 }
 
+// Close stops the traversal, even if the list has not been traversed to its end.
+func (iter GenericIter[T]) Close() {
+	if iter.CloseFunc != nil {
+		iter.CloseFunc()
+	}
+}
+
 type Commit struct {
 	Hash    string
 	Message string
@@ -35,6 +50,8 @@ type Commit struct {
 type Tag struct {
 	Name string
 	Hash string
+	// Version of the Module the Tag was created for.
+	Version string
 }
 
 // A vcs Repository.
@@ -45,7 +62,20 @@ type Repository interface {
 	Head() (string, error)
 	// History retrieves a repository's Commit log from a specific Commit hash as an Iter.
 	// History order is from newest(first) to lowest(last).
+	// The Iter has to be closed, once it is no longer needed.
 	History(opts HistoryOptions) (*GenericIter[*Commit], error)
+	// Commit records the changes of the given files on the current branch.
+	Commit(message string, opts CommitOptions) (*Commit, error)
+	// ResolveRevision returns the hash of the Commit a revision like a branch, Tag or HEAD~1 refers to.
+	ResolveRevision(revision string) (string, error)
+	// CommitDate returns the committer date of the Commit a revision refers to.
+	CommitDate(revision string) (time.Time, error)
+	// ReadFile returns the content of a file at a revision, e.g. a branch.
+	ReadFile(revision string, file string) ([]byte, error)
+	// CommitFiles commits files to a branch without touching the working tree.
+	// The branch is created, if it does not exist yet.
+	// A missing local branch is created on top of its remote tracking branch origin/<branch>, if it has been fetched.
+	CommitFiles(branch string, message string, files map[string][]byte) (*Commit, error)
 	// Tag creates a specific important point(Tag) in a repository's history.
 	Tag(version string, opts TagOptions) (*Tag, error)
 	// GetTag retrieves a specific important point(Tag) from a repository's history.
@@ -60,6 +90,8 @@ type Repository interface {
 type GoGitRepository struct {
 	name       string
 	repository *git.Repository
+	deepen     *DeepenOptions
+	tagFormats TagFormats
 }
 
 var _ Repository = GoGitRepository{}
@@ -78,6 +110,14 @@ type HistoryOptions struct {
 	Hash string
 	// A Module is just an application (directory) inside a mono repository.
 	Module string
+	// Paths replace the Module directory as the filter of the log.
+	Paths ModulePaths
+}
+
+// WithTagFormats returns a copy of the repository, which names Tags and parses their names with the given formats.
+func (repo GoGitRepository) WithTagFormats(formats TagFormats) GoGitRepository {
+	repo.tagFormats = formats
+	return repo
 }
 
 func (repo GoGitRepository) Name() string {
@@ -98,22 +138,29 @@ var (
 )
 
 func (repo GoGitRepository) History(opts HistoryOptions) (*GenericIter[*Commit], error) {
-	var filter func(path string) bool
-	if opts.Module != "" {
-		filter = func(path string) bool {
-			return strings.HasPrefix(path, modulePrefix(opts.Module))
-		}
-	}
+	filter := opts.Paths.filter(opts.Module)
 	var from plumbing.Hash
 	if opts.Hash != "" {
-		from = plumbing.NewHash(opts.Hash)
-		if from == plumbing.ZeroHash {
-			return nil, ErrUnrecognizedHash
+		var err error
+		from, err = parseHash(opts.Hash)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	newTagCommitIter, err := repo.repository.Log(&git.LogOptions{From: from, PathFilter: filter})
+	log, err := repo.repository.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return nil, err
+	}
+
+	// the depth of a shallow boundary counts all Commits, not only those passing the path filter
+	walked := &countingCommitIter{CommitIter: log}
+	var newTagCommitIter object.CommitIter = walked
+	if filter != nil {
+		newTagCommitIter = object.NewCommitPathIterFromIter(filter, walked, false)
+	}
 
+	shallow, err := repo.isShallow()
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +172,10 @@ func (repo GoGitRepository) History(opts HistoryOptions) (*GenericIter[*Commit],
 				return nil, ErrEndOfHistory
 			}
 
+			if shallow && errors.Is(err, plumbing.ErrObjectNotFound) {
+				return nil, &ShallowHistoryError{Depth: walked.count}
+			}
+
 			if err != nil {
 				return nil, err
 			}
@@ -134,6 +185,105 @@ func (repo GoGitRepository) History(opts HistoryOptions) (*GenericIter[*Commit],
 				Message: commit.Message,
 			}, nil
 		},
+		CloseFunc: newTagCommitIter.Close,
+	}, nil
+}
+
+// countingCommitIter counts the Commits it has walked.
+type countingCommitIter struct {
+	object.CommitIter
+	count int
+}
+
+func (iter *countingCommitIter) Next() (*object.Commit, error) {
+	commit, err := iter.CommitIter.Next()
+	if err == nil {
+		iter.count++
+	}
+	return commit, err
+}
+
+// Optional parameters for committing.
+type CommitOptions struct {
+	// Paths of the changed files relative to the repository root.
+	Paths []string
+}
+
+func (repo GoGitRepository) Commit(message string, opts CommitOptions) (*Commit, error) {
+	workTree, err := repo.repository.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := repo.signature()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(opts.Paths))
+	for _, path := range opts.Paths {
+		if _, err := workTree.Add(path); err != nil {
+			return nil, err
+		}
+		content, err := util.ReadFile(workTree.Filesystem, path)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(path)] = content
+	}
+
+	// like git commit -- <paths>, only the given files are committed, even if others are staged
+	headRef, err := repo.repository.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return nil, err
+	}
+	branchRef := plumbing.HEAD
+	if headRef.Type() == plumbing.SymbolicReference {
+		branchRef = headRef.Target()
+	}
+
+	var parents []plumbing.Hash
+	var baseTree *object.Tree
+	head, err := repo.repository.Reference(branchRef, true)
+	switch {
+	case errors.Is(err, plumbing.ErrReferenceNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		parent, err := repo.repository.CommitObject(head.Hash())
+		if err != nil {
+			return nil, err
+		}
+		baseTree, err = parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, parent.Hash)
+	}
+
+	treeHash, err := repo.writeTree(baseTree, files)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.storeObject(&object.Commit{
+		Author:       *signature,
+		Committer:    *signature,
+		Message:      message,
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.repository.Storer.SetReference(plumbing.NewHashReference(branchRef, hash)); err != nil {
+		return nil, err
+	}
+
+	return &Commit{
+		Hash:    hash.String(),
+		Message: message,
 	}, nil
 }
 
@@ -148,13 +298,34 @@ type TagOptions struct {
 	Hash string
 	// A Module is just an application (directory) inside a mono repository.
 	Module string
+	// TagModule is the Module the Tag name is prefixed with, if it differs from the Module,
+	// e.g. for Go modules in major version subdirectories. RootModule refers to the repository root.
+	TagModule string
+	// Paths replace the Module directory when looking for the latest Commit of the Module.
+	Paths ModulePaths
+}
+
+// RootModule refers to the repository root in options, where an empty Module means that the option is not set.
+const RootModule = "."
+
+// resolveTagModule returns the Module Tags are prefixed with.
+func resolveTagModule(module string, tagModule string) string {
+	switch tagModule {
+	case "":
+		return module
+	case RootModule:
+		return ""
+	default:
+		return tagModule
+	}
 }
 
 func (repo GoGitRepository) Tag(version string, opts TagOptions) (*Tag, error) {
-	history, err := repo.History(HistoryOptions{Hash: opts.Hash, Module: opts.Module})
+	history, err := repo.History(HistoryOptions{Hash: opts.Hash, Module: opts.Module, Paths: opts.Paths})
 	if err != nil {
 		return nil, err
 	}
+	defer history.Close()
 
 	latestCommit, err := history.Next()
 	if err != nil {
@@ -162,15 +333,20 @@ func (repo GoGitRepository) Tag(version string, opts TagOptions) (*Tag, error) {
 	}
 
 	hash := plumbing.NewHash(latestCommit.Hash)
-	tagName := tagName(version, opts.Module)
+	tagName, err := repo.tagFormats.name(resolveTagModule(opts.Module, opts.TagModule), version)
+	if err != nil {
+		return nil, err
+	}
+
 	tag, err := repo.repository.CreateTag(tagName, hash, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Tag{
-		Name: tag.Name().Short(),
-		Hash: tag.Hash().String(),
+		Name:    tag.Name().Short(),
+		Hash:    tag.Hash().String(),
+		Version: version,
 	}, nil
 }
 
@@ -183,7 +359,12 @@ type GetTagOptions struct {
 var ErrTagNotFound = errors.New("tag not found")
 
 func (repo GoGitRepository) GetTag(version string, opts GetTagOptions) (*Tag, error) {
-	tag, err := repo.repository.Tag(tagName(version, opts.Module))
+	tagName, err := repo.tagFormats.name(opts.Module, version)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := repo.repository.Tag(tagName)
 	if errors.Is(err, git.ErrTagNotFound) {
 		return nil, ErrTagNotFound
 	}
@@ -193,8 +374,9 @@ func (repo GoGitRepository) GetTag(version string, opts GetTagOptions) (*Tag, er
 	}
 
 	return &Tag{
-		Name: tag.Name().Short(),
-		Hash: tag.Hash().String(),
+		Name:    tag.Name().Short(),
+		Hash:    tag.Hash().String(),
+		Version: version,
 	}, nil
 }
 
@@ -204,39 +386,28 @@ func (repo GoGitRepository) GetTags(opts GetTagOptions) ([]Tag, error) {
 		return nil, err
 	}
 
-	var prefix string
-	if opts.Module != "" {
-		prefix = modulePrefix(opts.Module)
-	}
-
 	var moduleTags []Tag
 	if err := tags.ForEach(func(ref *plumbing.Reference) error {
-		if strings.HasPrefix(ref.Name().Short(), prefix) {
-			moduleTags = append(moduleTags, Tag{
-				Name: ref.Name().Short(),
-				Hash: ref.Hash().String(),
-			})
+		if tag, ok := repo.tagFormats.moduleTag(opts.Module, ref.Name().Short(), ref.Hash().String()); ok {
+			moduleTags = append(moduleTags, tag)
 		}
 		return nil
 	}); err != nil {
 		return nil, err
 	}
 
-	sort.Slice(moduleTags, func(i, j int) bool {
-		namesWithoutPrefix1 := strings.SplitAfter(moduleTags[i].Name, "/")
-		nameWithoutPrefix1 := namesWithoutPrefix1[len(namesWithoutPrefix1)-1]
-
-		namesWithoutPrefix2 := strings.SplitAfter(moduleTags[j].Name, "/")
-		nameWithoutPrefix2 := namesWithoutPrefix2[len(namesWithoutPrefix2)-1]
+	sortTags(moduleTags)
 
-		v1 := Version{version: nameWithoutPrefix1}
-		v2 := Version{version: nameWithoutPrefix2}
+	return moduleTags, nil
+}
 
-		greater, _ := v1.Gt(v2)
+// sortTags sorts Tags by their versions (highest first).
+func sortTags(tags []Tag) {
+	sort.Slice(tags, func(i, j int) bool {
+		// the Tags of the root Module include the Tags of nested Modules, whose versions keep their directory, e.g. subdir/v1.0.0
+		greater, _ := Version{version: path.Base(tags[i].Version)}.Gt(Version{version: path.Base(tags[j].Version)})
 		return greater
 	})
-
-	return moduleTags, nil
 }
 
 type Version struct {
@@ -244,56 +415,16 @@ type Version struct {
 }
 
 func (v Version) Gt(version Version) (bool, error) {
-	versionPrefix := "v"
-	v1 := strings.TrimPrefix(v.version, versionPrefix)
-	v2 := strings.TrimPrefix(version.version, versionPrefix)
-
-	versionSep := "."
-	v1Split := strings.Split(v1, versionSep)
-	v2Split := strings.Split(v2, versionSep)
-
-	major1, err := strconv.Atoi(v1Split[0])
+	major1, minor1, patch1, err := v.semVer()
 	if err != nil {
 		return false, err
 	}
 
-	major2, err := strconv.Atoi(v2Split[0])
+	major2, minor2, patch2, err := version.semVer()
 	if err != nil {
 		return false, err
 	}
 
-	minor1 := 0
-	if len(v1Split) > 1 {
-		minor1, err = strconv.Atoi(v1Split[1])
-		if err != nil {
-			return false, err
-		}
-	}
-
-	patch1 := 0
-	if len(v1Split) > 2 {
-		patch1, err = strconv.Atoi(v1Split[2])
-		if err != nil {
-			return false, err
-		}
-	}
-
-	minor2 := 0
-	if len(v2Split) > 1 {
-		minor2, err = strconv.Atoi(v2Split[1])
-		if err != nil {
-			return false, err
-		}
-	}
-
-	patch2 := 0
-	if len(v2Split) > 2 {
-		patch2, err = strconv.Atoi(v2Split[2])
-		if err != nil {
-			return false, err
-		}
-	}
-
 	isMajorGreater := major1 > major2
 	if isMajorGreater {
 		return true, nil
@@ -314,7 +445,47 @@ func (v Version) Gt(version Version) (bool, error) {
 		return false, nil
 	}
 
-	return patch1 > patch2, nil
+	isPatchGreater := patch1 > patch2
+	if isPatchGreater || patch1 < patch2 {
+		return isPatchGreater, nil
+	}
+
+	// a pre-release has a lower precedence than its release, e.g. v1.0.0-rc.1 < v1.0.0
+	return semver.Compare(InitialVersion+v.prerelease(), InitialVersion+version.prerelease()) > 0, nil
+}
+
+// semVer splits the version into its major, minor and patch numbers, ignoring its pre-release and build metadata.
+// Missing minor and patch numbers are treated as 0.
+func (v Version) semVer() (int, int, int, error) {
+	versionSep := "."
+	split := strings.Split(v.core(), versionSep)
+
+	numbers := [3]int{}
+	for i := 0; i < len(numbers) && i < len(split); i++ {
+		number, err := strconv.Atoi(split[i])
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		numbers[i] = number
+	}
+
+	return numbers[0], numbers[1], numbers[2], nil
+}
+
+// core returns the version without its "v" prefix, pre-release and build metadata, e.g. 1.2.3 of v1.2.3-rc.1+build.
+func (v Version) core() string {
+	core, _, _ := strings.Cut(strings.TrimPrefix(v.version, "v"), "+")
+	core, _, _ = strings.Cut(core, "-")
+	return core
+}
+
+// prerelease returns the pre-release of the version including its leading "-", or an empty string if it is a release.
+func (v Version) prerelease() string {
+	version, _, _ := strings.Cut(v.version, "+")
+	if _, prerelease, found := strings.Cut(version, "-"); found {
+		return "-" + prerelease
+	}
+	return ""
 }
 
 func tagName(name string, module string) string {
@@ -333,44 +504,99 @@ func tagName(name string, module string) string {
 type DiffOptions struct {
 	// A Module is just an application (directory) inside a mono repository.
 	Module string
+	// Paths replace the Module directory as the filter of the Commits.
+	Paths ModulePaths
+	// When FirstParent is set, only the first parent of merge commits is followed,
+	// which results in the merge (PR) commits of the main line instead of every branch commit.
+	FirstParent bool
 }
 
+// Diff returns the Commits reachable from newerTag, but not from olderTag, newest first.
+// The range is determined by walking the commit graph first, and only the Commits inside of it are checked against the Module,
+// so that the cost depends on the size of the range rather than on the size of the whole history.
+// In shallow clones a ShallowHistoryError is returned, if olderTag is not reachable, unless the repository automatically deepens its history.
 func (repo GoGitRepository) Diff(newerTag Tag, olderTag *Tag, opts DiffOptions) ([]*Commit, error) {
-	newHistoryIter, err := repo.History(HistoryOptions{Hash: newerTag.Hash, Module: opts.Module})
+	// a missing older Commit is reported with a depth of 0, which is still deepened once
+	previousDepth := -1
+	for {
+		commits, err := repo.diff(newerTag, olderTag, opts)
+
+		var shallowErr *ShallowHistoryError
+		if repo.deepen == nil || !errors.As(err, &shallowErr) {
+			return commits, err
+		}
+
+		// the remote did not provide any more history
+		if shallowErr.Depth <= previousDepth {
+			return []*Commit{}, err
+		}
+		previousDepth = shallowErr.Depth
+
+		if err := repo.deepenHistory(shallowErr); err != nil {
+			return []*Commit{}, err
+		}
+	}
+}
+
+func (repo GoGitRepository) diff(newerTag Tag, olderTag *Tag, opts DiffOptions) ([]*Commit, error) {
+	newHash, err := parseHash(newerTag.Hash)
 	if err != nil {
 		return []*Commit{}, err
 	}
 
-	var oldCommit *Commit
+	var oldHash plumbing.Hash
 	if olderTag != nil {
-		oldHistoryIter, err := repo.History(HistoryOptions{Hash: olderTag.Hash, Module: opts.Module})
+		oldHash, err = parseHash(olderTag.Hash)
 		if err != nil {
 			return []*Commit{}, err
 		}
+	}
 
-		oldCommit, err = oldHistoryIter.Next()
-		if err != nil && !errors.Is(err, ErrEndOfHistory) {
-			return []*Commit{}, err
-		}
+	shallow, err := repo.isShallow()
+	if err != nil {
+		return []*Commit{}, err
 	}
 
-	commitDiffs := make([]*Commit, 0, 20)
-	for {
-		newCommit, err := newHistoryIter.Next()
-		if err != nil && !errors.Is(err, ErrEndOfHistory) {
-			return []*Commit{}, err
-		}
+	index, closeIndex := repo.commitNodeIndex()
+	defer closeIndex()
+
+	commitsInRange, err := commitRange(index, newHash, oldHash, shallow)
+	if err != nil {
+		return []*Commit{}, err
+	}
+	filter := opts.Paths.filter(opts.Module)
 
-		if newCommit == nil || errors.Is(err, ErrEndOfHistory) {
-			break
+	ordered := topoOrder(newHash, commitsInRange, opts.FirstParent)
+	commitDiffs := make([]*Commit, 0, len(ordered))
+	for _, node := range ordered {
+		if filter != nil {
+			touched, err := touches(node, filter, opts.FirstParent)
+			if err != nil {
+				return []*Commit{}, err
+			}
+			if !touched {
+				continue
+			}
 		}
 
-		if oldCommit != nil && newCommit.Hash == oldCommit.Hash {
-			break
+		commit, err := node.Commit()
+		if err != nil {
+			return []*Commit{}, err
 		}
 
-		commitDiffs = append(commitDiffs, newCommit)
+		commitDiffs = append(commitDiffs, &Commit{
+			Hash:    commit.Hash.String(),
+			Message: commit.Message,
+		})
 	}
 
 	return commitDiffs, nil
 }
+
+func parseHash(hash string) (plumbing.Hash, error) {
+	parsed := plumbing.NewHash(hash)
+	if parsed == plumbing.ZeroHash {
+		return plumbing.ZeroHash, ErrUnrecognizedHash
+	}
+	return parsed, nil
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,25 +17,78 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
-	repository Repository
-	commits    []*Commit
-	tags       []Tag
+	repository    Repository
+	repositoryDir string
+	commits       []*Commit
+	tags          []Tag
 
 	lenCommits int
 )
 
 func TestMain(m *testing.M) {
-	repository, commits, tags, lenCommits = newRepo(false)
-	os.Exit(m.Run())
+	var err error
+	repositoryDir, err = os.MkdirTemp("", "monoreleaser")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	repository, commits, tags, lenCommits = newRepo(repositoryDir, false)
+	code := m.Run()
+	os.RemoveAll(repositoryDir)
+	os.Exit(code)
 }
 
-func newRepo(empty bool) (GoGitRepository, []*Commit, []Tag, int) {
+// sharedRepo returns the directory of the repository shared by all tests, which must not be modified.
+func sharedRepo(_ *testing.T) string {
+	return repositoryDir
+}
+
+// forEachImplementation runs a test against every Repository implementation.
+// The setup function returns the directory of the git repository to test.
+func forEachImplementation(
+	t *testing.T,
+	setup func(t *testing.T) string,
+	test func(t *testing.T, repository Repository),
+) {
+	implementations := []struct {
+		name string
+		open func(t *testing.T, dir string) Repository
+	}{
+		{
+			name: "go-git",
+			open: func(t *testing.T, dir string) Repository {
+				gitRepository, err := git.PlainOpen(dir)
+				require.NoError(t, err)
+				return NewGoGitRepository("myrepo", gitRepository)
+			},
+		},
+		{
+			name: "git",
+			open: func(_ *testing.T, dir string) Repository {
+				return NewGitBinaryRepository("myrepo", dir)
+			},
+		},
+	}
+
+	for _, implementation := range implementations {
+		implementation := implementation
+		t.Run(implementation.name, func(t *testing.T) {
+			test(t, implementation.open(t, setup(t)))
+		})
+	}
+}
+
+func newRepo(dir string, empty bool) (GoGitRepository, []*Commit, []Tag, int) {
 	var commits []*Commit
 	var tags []Tag
-	gitRepository, _ := git.Init(memory.NewStorage(), memfs.New())
+	gitRepository, err := git.PlainInit(dir, false)
+	if err != nil {
+		log.Panic(err)
+	}
 	workTree, err := gitRepository.Worktree()
 	if err != nil {
 		log.Panic(err)
@@ -121,67 +176,92 @@ func newRepo(empty bool) (GoGitRepository, []*Commit, []Tag, int) {
 }
 
 func TestHistory(t *testing.T) {
-	commitIter, err := repository.History(HistoryOptions{})
-	assert.NoError(t, err)
-
-	for i := 0; i < lenCommits; i++ {
-		commit, err := commitIter.Next()
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		commitIter, err := repository.History(HistoryOptions{})
 		assert.NoError(t, err)
-		assert.Equal(t, commits[lenCommits-i-1], commit)
-	}
 
-	commit, err := commitIter.Next()
-	assert.ErrorIs(t, err, ErrEndOfHistory)
-	assert.Nil(t, commit)
+		for i := 0; i < lenCommits; i++ {
+			commit, err := commitIter.Next()
+			assert.NoError(t, err)
+			assert.Equal(t, commits[lenCommits-i-1], commit)
+		}
+
+		commit, err := commitIter.Next()
+		assert.ErrorIs(t, err, ErrEndOfHistory)
+		assert.Nil(t, commit)
+	})
 }
 
 func TestHistory_Module(t *testing.T) {
-	commitIter, err := repository.History(HistoryOptions{Module: "subdir"})
-	assert.NoError(t, err)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		commitIter, err := repository.History(HistoryOptions{Module: "subdir"})
+		assert.NoError(t, err)
 
-	commit, err := commitIter.Next()
-	assert.NoError(t, err)
-	assert.Equal(t, commits[lenCommits-1], commit)
+		commit, err := commitIter.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, commits[lenCommits-1], commit)
 
-	commit, err = commitIter.Next()
-	assert.NoError(t, err)
-	assert.Equal(t, commits[0], commit)
+		commit, err = commitIter.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, commits[0], commit)
 
-	commit, err = commitIter.Next()
-	assert.ErrorIs(t, err, ErrEndOfHistory)
-	assert.Nil(t, commit)
+		commit, err = commitIter.Next()
+		assert.ErrorIs(t, err, ErrEndOfHistory)
+		assert.Nil(t, commit)
+	})
 }
 
 func TestHistory_Hash(t *testing.T) {
-	commitIter, err := repository.History(HistoryOptions{Hash: commits[1].Hash})
-	assert.NoError(t, err)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		commitIter, err := repository.History(HistoryOptions{Hash: commits[1].Hash})
+		assert.NoError(t, err)
 
-	commit, err := commitIter.Next()
-	assert.NoError(t, err)
-	assert.Equal(t, commits[1], commit)
+		commit, err := commitIter.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, commits[1], commit)
 
-	commit, err = commitIter.Next()
-	assert.NoError(t, err)
-	assert.Equal(t, commits[0], commit)
+		commit, err = commitIter.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, commits[0], commit)
+
+		commit, err = commitIter.Next()
+		assert.ErrorIs(t, err, ErrEndOfHistory)
+		assert.Nil(t, commit)
+	})
+}
+
+func TestHistory_Close(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		commitIter, err := repository.History(HistoryOptions{})
+		require.NoError(t, err)
+
+		commit, err := commitIter.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, commits[lenCommits-1], commit)
 
-	commit, err = commitIter.Next()
-	assert.ErrorIs(t, err, ErrEndOfHistory)
-	assert.Nil(t, commit)
+		// closing a History, which has not been read to its end, more than once is fine
+		commitIter.Close()
+		commitIter.Close()
+	})
 }
 
 func TestHistory_Module_NotFound(t *testing.T) {
-	commitIter, err := repository.History(HistoryOptions{Hash: "bla"})
-	assert.ErrorIs(t, err, ErrUnrecognizedHash)
-	assert.Nil(t, commitIter)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		commitIter, err := repository.History(HistoryOptions{Hash: "bla"})
+		assert.ErrorIs(t, err, ErrUnrecognizedHash)
+		assert.Nil(t, commitIter)
+	})
 }
 
 func TestHistory_Hash_NotFound(t *testing.T) {
-	commitIter, err := repository.History(HistoryOptions{Module: "bla"})
-	assert.NoError(t, err)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		commitIter, err := repository.History(HistoryOptions{Module: "bla"})
+		assert.NoError(t, err)
 
-	commit, err := commitIter.Next()
-	assert.ErrorIs(t, err, ErrEndOfHistory)
-	assert.Nil(t, commit)
+		commit, err := commitIter.Next()
+		assert.ErrorIs(t, err, ErrEndOfHistory)
+		assert.Nil(t, commit)
+	})
 }
 
 var diffCommits []*Commit
@@ -200,141 +280,337 @@ func BenchmarkDiff(b *testing.B) {
 	diffCommits = c
 }
 
-func TestDiff(t *testing.T) {
-	newTag := Tag{Hash: commits[lenCommits-1].Hash}
-	oldTag := &Tag{Hash: commits[0].Hash}
-	diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{})
-	assert.NoError(t, err)
+var (
+	largeRepository     GoGitRepository
+	largeCommits        []*Commit
+	largeRepositoryOnce sync.Once
+)
 
-	assert.Len(t, diffCommits, lenCommits-1)
+// newLargeRepo creates a history of the given size, where each Commit changes a file in one of ten modules.
+// The module "rare" is only changed by the first and the fifth newest Commit.
+func newLargeRepo(size int) (GoGitRepository, []*Commit) {
+	gitRepository, _ := git.Init(memory.NewStorage(), memfs.New())
+	workTree, err := gitRepository.Worktree()
+	if err != nil {
+		log.Panic(err)
+	}
 
-	for i := len(diffCommits) - 1; i >= 0; i-- {
-		assert.Equal(t, commits[lenCommits-i-1], diffCommits[i])
-		assert.NotEqual(t, diffCommits[i].Message, "feat: oldest")
+	commits := make([]*Commit, 0, size)
+	for i := 0; i < size; i++ {
+		name := fmt.Sprintf("module%v/file", i%10)
+		if i == 0 || i == size-5 {
+			name = "rare/file"
+		}
+		file, err := workTree.Filesystem.Create(name)
+		if err != nil {
+			log.Panic(err)
+		}
+		if _, err := file.Write([]byte(strconv.Itoa(i))); err != nil {
+			log.Panic(err)
+		}
+		file.Close()
+		workTree.Add(name)
+
+		message := fmt.Sprintf("feat: change %v", i)
+		hash, err := workTree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{
+				Name:  "orca",
+				Email: "orca-dev@mail.com",
+				When:  time.Unix(int64(i), 0),
+			},
+		})
+		if err != nil {
+			log.Panic(err)
+		}
+		commits = append(commits, &Commit{Hash: hash.String(), Message: message})
 	}
+
+	return NewGoGitRepository("myrepo", gitRepository), commits
 }
 
-func TestDiff_NoOlderTagProvided(t *testing.T) {
-	newTag := Tag{Hash: commits[lenCommits-1].Hash}
-	diffCommits, err := repository.Diff(newTag, nil, DiffOptions{})
-	assert.NoError(t, err)
+func largeRepo() (GoGitRepository, []*Commit) {
+	largeRepositoryOnce.Do(func() {
+		largeRepository, largeCommits = newLargeRepo(3000)
+	})
+	return largeRepository, largeCommits
+}
+
+func BenchmarkDiff_LargeHistory_RarelyChangedModule(b *testing.B) {
+	repository, commits := largeRepo()
+	b.ReportAllocs()
+	b.ResetTimer()
+	var c []*Commit
+	newTag := Tag{Hash: commits[len(commits)-1].Hash}
+	oldTag := &Tag{Hash: commits[len(commits)-50].Hash}
+	opts := DiffOptions{Module: "rare"}
+	for i := 0; i < b.N; i++ {
+		c, _ = repository.Diff(newTag, oldTag, opts)
+	}
 
-	assert.Len(t, diffCommits, lenCommits)
+	diffCommits = c
+}
 
-	for i := lenCommits - 1; i >= 0; i-- {
-		assert.Equal(t, commits[lenCommits-i-1], diffCommits[i])
+// BenchmarkHistory_LargeHistory_RarelyChangedModule measures the previous approach of Diff for comparison,
+// which scanned the path filtered histories of both Tags and therefore diffed the trees of the whole history.
+func BenchmarkHistory_LargeHistory_RarelyChangedModule(b *testing.B) {
+	repository, commits := largeRepo()
+	b.ReportAllocs()
+	b.ResetTimer()
+	var c []*Commit
+	newHash := commits[len(commits)-1].Hash
+	opts := HistoryOptions{Hash: newHash, Module: "rare"}
+	for i := 0; i < b.N; i++ {
+		oldHistory, _ := repository.History(HistoryOptions{Hash: commits[len(commits)-50].Hash, Module: "rare"})
+		oldCommit, _ := oldHistory.Next()
+		oldHistory.Close()
+		newHistory, _ := repository.History(opts)
+		c = c[:0]
+		for {
+			commit, err := newHistory.Next()
+			if err != nil || commit.Hash == oldCommit.Hash {
+				break
+			}
+			c = append(c, commit)
+		}
+		newHistory.Close()
 	}
+
+	diffCommits = c
+}
+
+func TestDiff_LargeHistory_Module(t *testing.T) {
+	repository, commits := largeRepo()
+	newTag := Tag{Hash: commits[len(commits)-1].Hash}
+	oldTag := &Tag{Hash: commits[len(commits)-50].Hash}
+
+	diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{Module: "rare"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Commit{commits[len(commits)-5]}, diffCommits)
+
+	diffCommits, err = repository.Diff(newTag, oldTag, DiffOptions{Module: "module0"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Commit{
+		commits[len(commits)-10],
+		commits[len(commits)-20],
+		commits[len(commits)-30],
+		commits[len(commits)-40],
+	}, diffCommits)
+}
+
+func TestDiff(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits[lenCommits-1].Hash}
+		oldTag := &Tag{Hash: commits[0].Hash}
+		diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{})
+		assert.NoError(t, err)
+
+		assert.Len(t, diffCommits, lenCommits-1)
+
+		for i := len(diffCommits) - 1; i >= 0; i-- {
+			assert.Equal(t, commits[lenCommits-i-1], diffCommits[i])
+			assert.NotEqual(t, diffCommits[i].Message, "feat: oldest")
+		}
+	})
+}
+
+func TestDiff_NoOlderTagProvided(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits[lenCommits-1].Hash}
+		diffCommits, err := repository.Diff(newTag, nil, DiffOptions{})
+		assert.NoError(t, err)
+
+		assert.Len(t, diffCommits, lenCommits)
+
+		for i := lenCommits - 1; i >= 0; i-- {
+			assert.Equal(t, commits[lenCommits-i-1], diffCommits[i])
+		}
+	})
 }
 
 func TestDiff_PathFilterSubDir(t *testing.T) {
-	newTag := Tag{Hash: commits[lenCommits-1].Hash}
-	oldTag := &Tag{Hash: commits[0].Hash}
-	diffCommits, _ := repository.Diff(newTag, oldTag, DiffOptions{Module: "subdir"})
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits[lenCommits-1].Hash}
+		oldTag := &Tag{Hash: commits[0].Hash}
+		diffCommits, _ := repository.Diff(newTag, oldTag, DiffOptions{Module: "subdir"})
 
-	assert.Len(t, commits, 12)
-	assert.Len(t, diffCommits, 1)
+		assert.Len(t, commits, 12)
+		assert.Len(t, diffCommits, 1)
 
-	assert.Equal(t, commits[lenCommits-1], diffCommits[0])
+		assert.Equal(t, commits[lenCommits-1], diffCommits[0])
+	})
 }
 
 func TestDiff_PathFilterSubDir_OldTagFromOtherDir(t *testing.T) {
-	newTag := Tag{Hash: commits[lenCommits-1].Hash}
-	oldTag := &Tag{Hash: commits[lenCommits-2].Hash}
-	diffCommits, _ := repository.Diff(newTag, oldTag, DiffOptions{Module: "subdir"})
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits[lenCommits-1].Hash}
+		oldTag := &Tag{Hash: commits[lenCommits-2].Hash}
+		diffCommits, _ := repository.Diff(newTag, oldTag, DiffOptions{Module: "subdir"})
 
-	assert.Len(t, commits, 12)
-	assert.Len(t, diffCommits, 1)
+		assert.Len(t, commits, 12)
+		assert.Len(t, diffCommits, 1)
 
-	assert.Equal(t, commits[lenCommits-1], diffCommits[0])
+		assert.Equal(t, commits[lenCommits-1], diffCommits[0])
+	})
 }
 
 func TestDiff_NoExistingTags(t *testing.T) {
-	newTag := Tag{Hash: "asdasd"}
-	oldTag := &Tag{Hash: "lkjhlkjh"}
-	diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{})
-	assert.Len(t, diffCommits, 0)
-	assert.ErrorIs(t, err, ErrUnrecognizedHash)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: "asdasd"}
+		oldTag := &Tag{Hash: "lkjhlkjh"}
+		diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{})
+		assert.Len(t, diffCommits, 0)
+		assert.ErrorIs(t, err, ErrUnrecognizedHash)
+	})
 }
 
 func TestHead(t *testing.T) {
-	head, err := repository.Head()
-	assert.NoError(t, err)
-	assert.Equal(t, commits[lenCommits-1].Hash, head)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		head, err := repository.Head()
+		assert.NoError(t, err)
+		assert.Equal(t, commits[lenCommits-1].Hash, head)
+	})
+}
+
+// freshRepo returns a setup function for forEachImplementation, which creates a new repository for every implementation.
+// The Commits of the latest created repository are stored in commits.
+func freshRepo(empty bool, commits *[]*Commit) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		dir := t.TempDir()
+		_, *commits, _, _ = newRepo(dir, empty)
+		return dir
+	}
+}
+
+func TestCommit_OnlyPaths(t *testing.T) {
+	var dir string
+	forEachImplementation(t, func(t *testing.T) string {
+		dir = versionFileRepo(t)
+		return dir
+	}, func(t *testing.T, repository Repository) {
+		gitRepository, err := git.PlainOpen(dir)
+		require.NoError(t, err)
+		workTree, err := gitRepository.Worktree()
+		require.NoError(t, err)
+		_, err = workTree.Add("untracked")
+		require.NoError(t, err)
+
+		commit, err := repository.Commit("chore: bump", CommitOptions{Paths: []string{filepath.Join("subdir", "package.json")}})
+		require.NoError(t, err)
+
+		head, err := repository.Head()
+		require.NoError(t, err)
+		assert.Equal(t, head, commit.Hash)
+		content, err := repository.ReadFile("HEAD", "subdir/package.json")
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": "1.0.0"}`, string(content))
+		// files of the parent are kept
+		_, err = repository.ReadFile("HEAD", "subdir/0")
+		assert.NoError(t, err)
+
+		// other staged files are neither committed nor unstaged
+		_, err = repository.ReadFile("HEAD", "untracked")
+		assert.ErrorIs(t, err, ErrFileNotFound)
+		status, err := workTree.Status()
+		require.NoError(t, err)
+		assert.Equal(t, git.Added, status.File("untracked").Staging)
+		_, changed := status["subdir/package.json"]
+		assert.False(t, changed)
+	})
 }
 
 func TestTag_LatestCommit(t *testing.T) {
-	repository, commits, _, lenCommits := newRepo(false)
-	tag, err := repository.Tag("mytag", TagOptions{})
-	assert.NoError(t, err)
-	assert.Equal(t, commits[lenCommits-1].Hash, tag.Hash)
-	assert.Equal(t, "mytag", tag.Name)
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(false, &commits), func(t *testing.T, repository Repository) {
+		lenCommits := len(commits)
+		tag, err := repository.Tag("mytag", TagOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, commits[lenCommits-1].Hash, tag.Hash)
+		assert.Equal(t, "mytag", tag.Name)
+	})
 }
 
 func TestTag_SecondLatestCommit(t *testing.T) {
-	repository, commits, _, lenCommits := newRepo(false)
-	secondNewestCommit := commits[lenCommits-2]
-	tag, err := repository.Tag("mytag", TagOptions{Hash: secondNewestCommit.Hash})
-	assert.NoError(t, err)
-	assert.Equal(t, secondNewestCommit.Hash, tag.Hash)
-	assert.Equal(t, "mytag", tag.Name)
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(false, &commits), func(t *testing.T, repository Repository) {
+		lenCommits := len(commits)
+		secondNewestCommit := commits[lenCommits-2]
+		tag, err := repository.Tag("mytag", TagOptions{Hash: secondNewestCommit.Hash})
+		assert.NoError(t, err)
+		assert.Equal(t, secondNewestCommit.Hash, tag.Hash)
+		assert.Equal(t, "mytag", tag.Name)
+	})
 }
 
 func TestTag_NoCommitHistory(t *testing.T) {
-	repository, _, _, _ := newRepo(true)
-	_, err := repository.Tag("mytag", TagOptions{})
-	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(true, &commits), func(t *testing.T, repository Repository) {
+		_, err := repository.Tag("mytag", TagOptions{})
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
 }
 
 func TestGetTag(t *testing.T) {
-	tag, err := repository.GetTag("v1.0.0", GetTagOptions{})
-	assert.NoError(t, err)
-	assert.Equal(t, commits[1].Hash, tag.Hash)
-	assert.Equal(t, "v1.0.0", tag.Name)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		tag, err := repository.GetTag("v1.0.0", GetTagOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, commits[1].Hash, tag.Hash)
+		assert.Equal(t, "v1.0.0", tag.Name)
+	})
 }
 
 func TestGetTag_Module(t *testing.T) {
-	tag, err := repository.GetTag("v0.0.0", GetTagOptions{Module: "subdir"})
-	assert.NoError(t, err)
-	assert.Equal(t, commits[0].Hash, tag.Hash)
-	assert.Equal(t, "subdir/v0.0.0", tag.Name)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		tag, err := repository.GetTag("v0.0.0", GetTagOptions{Module: "subdir"})
+		assert.NoError(t, err)
+		assert.Equal(t, commits[0].Hash, tag.Hash)
+		assert.Equal(t, "subdir/v0.0.0", tag.Name)
+	})
 }
 
 func TestGetTag_NotFound(t *testing.T) {
-	tag, err := repository.GetTag("sdjask", GetTagOptions{})
-	assert.ErrorIs(t, err, ErrTagNotFound)
-	assert.Nil(t, tag)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		tag, err := repository.GetTag("sdjask", GetTagOptions{})
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		assert.Nil(t, tag)
+	})
 }
 
 func TestGetTags(t *testing.T) {
-	mrTags, err := repository.GetTags(GetTagOptions{})
-	assert.NoError(t, err)
-	lenTags := len(mrTags)
-	assert.Equal(t, lenCommits, lenTags)
-	for i, tag := range mrTags {
-		assert.Equal(t, tags[len(tags)-i-1].Hash, tag.Hash)
-		assert.Equal(t, tags[len(tags)-i-1].Name, tag.Name)
-	}
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		mrTags, err := repository.GetTags(GetTagOptions{})
+		assert.NoError(t, err)
+		lenTags := len(mrTags)
+		assert.Equal(t, lenCommits, lenTags)
+		for i, tag := range mrTags {
+			assert.Equal(t, tags[len(tags)-i-1].Hash, tag.Hash)
+			assert.Equal(t, tags[len(tags)-i-1].Name, tag.Name)
+		}
+	})
 }
 
 func TestGetTags_Module(t *testing.T) {
-	mrTags, err := repository.GetTags(GetTagOptions{Module: "subdir"})
-	assert.NoError(t, err)
-	lenTags := len(mrTags)
-	assert.Equal(t, 2, lenTags)
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		mrTags, err := repository.GetTags(GetTagOptions{Module: "subdir"})
+		assert.NoError(t, err)
+		lenTags := len(mrTags)
+		assert.Equal(t, 2, lenTags)
 
-	assert.Equal(t, tags[len(tags)-1].Hash, mrTags[0].Hash)
-	assert.Equal(t, tags[len(tags)-1].Name, mrTags[0].Name)
+		assert.Equal(t, tags[len(tags)-1].Hash, mrTags[0].Hash)
+		assert.Equal(t, tags[len(tags)-1].Name, mrTags[0].Name)
 
-	assert.Equal(t, tags[0].Hash, mrTags[1].Hash)
-	assert.Equal(t, tags[0].Name, mrTags[1].Name)
+		assert.Equal(t, tags[0].Hash, mrTags[1].Hash)
+		assert.Equal(t, tags[0].Name, mrTags[1].Name)
+	})
 }
 
 func TestGetTags_NoTags(t *testing.T) {
-	repository, _, _, _ := newRepo(true)
-	tags, err := repository.GetTags(GetTagOptions{})
-	assert.NoError(t, err)
-	lenTags := len(tags)
-	assert.Equal(t, 0, lenTags)
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(true, &commits), func(t *testing.T, repository Repository) {
+		tags, err := repository.GetTags(GetTagOptions{})
+		assert.NoError(t, err)
+		lenTags := len(tags)
+		assert.Equal(t, 0, lenTags)
+	})
 }
 
 func TestVersion_Gt(t *testing.T) {
@@ -345,3 +621,167 @@ func TestVersion_Gt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, greater)
 }
+
+func TestVersion_Gt_Prerelease(t *testing.T) {
+	ordered := []string{"v1.0.0-alpha", "v1.0.0-alpha.1", "v1.0.0-beta", "v1.0.0-rc.1", "v1.0.0-rc.2", "v1.0.0", "v1.0.1-rc.1"}
+	for i := 1; i < len(ordered); i++ {
+		greater, err := Version{version: ordered[i]}.Gt(Version{version: ordered[i-1]})
+		assert.NoError(t, err)
+		assert.True(t, greater, "%s > %s", ordered[i], ordered[i-1])
+
+		lower, err := Version{version: ordered[i-1]}.Gt(Version{version: ordered[i]})
+		assert.NoError(t, err)
+		assert.False(t, lower, "%s < %s", ordered[i-1], ordered[i])
+	}
+}
+
+// newMergeRepo creates the following history, where M merges the feature branch (B, C) into the main line (A, D):
+//
+//	A - D - M
+//	 \     /
+//	  B - C
+func newMergeRepo(dir string) (GoGitRepository, map[string]*Commit) {
+	gitRepository, err := git.PlainInit(dir, false)
+	if err != nil {
+		log.Panic(err)
+	}
+	workTree, err := gitRepository.Worktree()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	commits := make(map[string]*Commit)
+	commit := func(name string, message string, files []string, parents []plumbing.Hash) plumbing.Hash {
+		for _, name := range files {
+			file, err := workTree.Filesystem.Create(name)
+			if err != nil {
+				log.Panic(err)
+			}
+			file.Close()
+			workTree.Add(name)
+		}
+
+		hash, err := workTree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{
+				Name:  "orca",
+				Email: "orca-dev@mail.com",
+				When:  time.Now(),
+			},
+			Parents: parents,
+		})
+		if err != nil {
+			log.Panic(err)
+		}
+
+		commits[name] = &Commit{Hash: hash.String(), Message: message}
+		return hash
+	}
+
+	commit("A", "feat: a", []string{"subdir/a"}, nil)
+	if err := workTree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}); err != nil {
+		log.Panic(err)
+	}
+	commit("B", "feat: b", []string{"subdir/b"}, nil)
+	c := commit("C", "fix: c", []string{"c"}, nil)
+	if err := workTree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}); err != nil {
+		log.Panic(err)
+	}
+	d := commit("D", "fix: d", []string{"subdir/d"}, nil)
+	commit("M", "Merge pull request #1 from feature", []string{"subdir/b", "c"}, []plumbing.Hash{d, c})
+
+	return NewGoGitRepository("myrepo", gitRepository), commits
+}
+
+// mergeRepo returns a setup function for forEachImplementation, which creates a new merge repository for every implementation.
+func mergeRepo(commits *map[string]*Commit) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		dir := t.TempDir()
+		_, *commits = newMergeRepo(dir)
+		return dir
+	}
+}
+
+func TestDiff_Merge_OldTagOnBranch(t *testing.T) {
+	var commits map[string]*Commit
+	forEachImplementation(t, mergeRepo(&commits), func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits["M"].Hash}
+		oldTag := &Tag{Hash: commits["C"].Hash}
+
+		diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits["M"], commits["D"]}, diffCommits)
+	})
+}
+
+func TestDiff_Merge_OldTagOnMainLine(t *testing.T) {
+	var commits map[string]*Commit
+	forEachImplementation(t, mergeRepo(&commits), func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits["M"].Hash}
+		oldTag := &Tag{Hash: commits["D"].Hash}
+
+		diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits["M"], commits["C"], commits["B"]}, diffCommits)
+	})
+}
+
+func TestDiff_Merge_PathFilterSubDir(t *testing.T) {
+	var commits map[string]*Commit
+	forEachImplementation(t, mergeRepo(&commits), func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits["M"].Hash}
+		oldTag := &Tag{Hash: commits["D"].Hash}
+
+		diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{Module: "subdir"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits["M"], commits["B"]}, diffCommits)
+	})
+}
+
+func TestDiff_Merge_NoOlderTagProvided_PathFilterSubDir(t *testing.T) {
+	var commits map[string]*Commit
+	forEachImplementation(t, mergeRepo(&commits), func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits["M"].Hash}
+
+		diffCommits, err := repository.Diff(newTag, nil, DiffOptions{Module: "subdir"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits["M"], commits["D"], commits["B"], commits["A"]}, diffCommits)
+	})
+}
+
+func TestDiff_Merge_FirstParent(t *testing.T) {
+	var commits map[string]*Commit
+	forEachImplementation(t, mergeRepo(&commits), func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits["M"].Hash}
+		oldTag := &Tag{Hash: commits["A"].Hash}
+
+		diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{FirstParent: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits["M"], commits["D"]}, diffCommits)
+	})
+}
+
+func TestDiff_Merge_FirstParent_PathFilter(t *testing.T) {
+	var commits map[string]*Commit
+	forEachImplementation(t, mergeRepo(&commits), func(t *testing.T, repository Repository) {
+		newTag := Tag{Hash: commits["M"].Hash}
+		oldTag := &Tag{Hash: commits["D"].Hash}
+
+		diffCommits, err := repository.Diff(newTag, oldTag, DiffOptions{Module: "subdir", FirstParent: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Commit{commits["M"]}, diffCommits)
+	})
+}
+
+func TestTag_TagModule(t *testing.T) {
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(false, &commits), func(t *testing.T, repository Repository) {
+		tag, err := repository.Tag("v2.0.0", TagOptions{Module: "subdir", TagModule: "lib"})
+		assert.NoError(t, err)
+		assert.Equal(t, commits[len(commits)-1].Hash, tag.Hash)
+		assert.Equal(t, "lib/v2.0.0", tag.Name)
+
+		tag, err = repository.Tag("v3.0.0", TagOptions{Module: "subdir", TagModule: RootModule})
+		assert.NoError(t, err)
+		assert.Equal(t, "v3.0.0", tag.Name)
+	})
+}
@@ -0,0 +1,102 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+var ErrShallowHistory = errors.New("history is cut off by a shallow clone")
+
+// A ShallowHistoryError is returned when a shallow clone does not contain enough history to reach a Commit, e.g. the previous Tag.
+type ShallowHistoryError struct {
+	// Depth is the number of Commits available until the shallow boundary was reached.
+	Depth int
+	// Since is the commit time of the Commit which could not be reached, if it is available locally.
+	Since *time.Time
+}
+
+var _ error = &ShallowHistoryError{}
+
+func (err *ShallowHistoryError) Error() string {
+	msg := fmt.Sprintf(
+		"%s after %d commits: fetch more history, e.g. with 'git fetch --deepen=%d'",
+		ErrShallowHistory,
+		err.Depth,
+		deepenBy(err.Depth),
+	)
+	if err.Since != nil {
+		msg += fmt.Sprintf(" or 'git fetch --shallow-since=%s'", err.Since.UTC().Format(time.RFC3339))
+	}
+	return msg
+}
+
+func (err *ShallowHistoryError) Is(target error) bool {
+	return target == ErrShallowHistory
+}
+
+// deepenBy doubles the available history, so that the number of fetches grows logarithmically with the missing history.
+func deepenBy(depth int) int {
+	if depth < 50 {
+		return 50
+	}
+	return depth
+}
+
+// Options for automatically fetching missing history of shallow clones.
+type DeepenOptions struct {
+	// Name of the remote to fetch from.
+	// If this option is not set, "origin" will be used.
+	Remote string
+	// Auth is used to authenticate against the remote.
+	Auth transport.AuthMethod
+	// MaxDepth limits how deep the history will be fetched.
+	// If this option is not set, the history will be deepened until the missing Commits are reachable.
+	MaxDepth int
+}
+
+// WithAutoDeepen returns a copy of the repository, which fetches missing history from the remote,
+// whenever a shallow boundary prevents reaching the previous Tag.
+func (repo GoGitRepository) WithAutoDeepen(opts DeepenOptions) GoGitRepository {
+	if opts.Remote == "" {
+		opts.Remote = git.DefaultRemoteName
+	}
+	repo.deepen = &opts
+	return repo
+}
+
+func (repo GoGitRepository) isShallow() (bool, error) {
+	shallows, err := repo.repository.Storer.Shallow()
+	if err != nil {
+		return false, err
+	}
+	return len(shallows) != 0, nil
+}
+
+// deepenHistory fetches more history from the remote than is available locally.
+// It returns the original error, if the configured maximum depth is already reached.
+func (repo GoGitRepository) deepenHistory(shallowErr *ShallowHistoryError) error {
+	depth := shallowErr.Depth + deepenBy(shallowErr.Depth)
+	if repo.deepen.MaxDepth != 0 && shallowErr.Depth >= repo.deepen.MaxDepth {
+		return shallowErr
+	}
+	if repo.deepen.MaxDepth != 0 && depth > repo.deepen.MaxDepth {
+		depth = repo.deepen.MaxDepth
+	}
+
+	err := repo.repository.Fetch(&git.FetchOptions{
+		RemoteName: repo.deepen.Remote,
+		Auth:       repo.deepen.Auth,
+		Depth:      depth,
+		Tags:       git.AllTags,
+	})
+	// go-git reports fetches which did not update any references as up to date, even if history was deepened
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,59 @@
+package monoreleaser
+
+import (
+	"fmt"
+)
+
+// Semantics are all Semantics ordered by their impact on the version, e.g. to list Changes grouped by their Semantic.
+var Semantics = []Semantic{Major, Minor, Patch, Revert, Unknown}
+
+// A ModuleStatus shows what is unreleased in a Module.
+type ModuleStatus struct {
+	// A Module is just an application (directory) inside a mono repository.
+	Module string
+	// Name is the logical name of a configured Module, like Module.Name.
+	Name string
+	// LatestTag is the latest release of the Module, or nil if it has never been released.
+	LatestTag *Tag
+	// Changes since the LatestTag, newest first.
+	Changes []Change
+	// NextVersion is the version computed from the Changes, or empty if there is nothing to release.
+	NextVersion string
+}
+
+// GetStatus returns the unreleased Changes of a Module and the version they would be released with.
+// Nothing is changed in the repository.
+func GetStatus(repository Repository, module Module, opts DiffOptions) (*ModuleStatus, error) {
+	latestTag, commits, err := Unreleased(repository, module, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ModuleName(module.Path), err)
+	}
+
+	var latestVersion string
+	if latestTag != nil {
+		latestVersion = latestTag.Version
+	}
+
+	changes := Extract(commits)
+	nextVersion, _, err := NextVersion(latestVersion, changes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ModuleName(module.Path), err)
+	}
+
+	return &ModuleStatus{
+		Module:      module.Path,
+		Name:        module.Name,
+		LatestTag:   latestTag,
+		Changes:     changes,
+		NextVersion: nextVersion,
+	}, nil
+}
+
+// ChangesBySemantic groups the Changes by their Semantic, keeping their order.
+func (status ModuleStatus) ChangesBySemantic() map[Semantic][]Change {
+	groups := make(map[Semantic][]Change)
+	for _, change := range status.Changes {
+		groups[change.Semantic] = append(groups[change.Semantic], change)
+	}
+	return groups
+}
@@ -0,0 +1,38 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatus(t *testing.T) {
+	var unreleased *Commit
+	forEachImplementation(t, unreleasedRepo(&unreleased), func(t *testing.T, repository Repository) {
+		status, err := GetStatus(repository, Module{Path: "subdir"}, DiffOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "subdir", status.Module)
+		assert.Equal(t, "subdir/v1.11.0", status.LatestTag.Name)
+		assert.Equal(t, "v1.11.1", status.NextVersion)
+		assert.Equal(t, map[Semantic][]Change{
+			Patch: {{Message: unreleased.Message, Hash: unreleased.Hash, Semantic: Patch}},
+		}, status.ChangesBySemantic())
+
+		status, err = GetStatus(repository, Module{Path: ""}, DiffOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "v1.10.0", status.LatestTag.Name)
+		assert.Equal(t, "v1.11.0", status.NextVersion)
+		assert.Len(t, status.Changes, 2)
+	})
+}
+
+func TestGetStatus_NothingToRelease(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		status, err := GetStatus(repository, Module{Path: "unknown"}, DiffOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, status.LatestTag)
+		assert.Empty(t, status.NextVersion)
+		assert.Empty(t, status.ChangesBySemantic())
+	})
+}
@@ -0,0 +1,125 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var ErrInvalidTagFormat = errors.New("invalid tag format")
+
+// versionPattern matches the version inside of a Tag name, which has no leading "v" in a TagFormat.
+const versionPattern = `(\d+(?:\.\d+)*(?:[-+][0-9A-Za-z.+-]+)?)`
+
+// versionSentinel is rendered in place of the version to find out where the version is located in a Tag name.
+const versionSentinel = "\x00version\x00"
+
+// A TagFormat names Tags by a text/template, e.g. "{{.Module}}@v{{.Version}}".
+// The template is executed with the Module and the Version, whose leading "v" is stripped.
+// The Module of the repository root is empty, which templates can handle with e.g. "{{if .Module}}{{.Module}}@{{end}}v{{.Version}}".
+type TagFormat struct {
+	template *template.Template
+}
+
+type tagFormatData struct {
+	Module  string
+	Version string
+}
+
+// NewTagFormat parses a tag name template.
+func NewTagFormat(format string) (*TagFormat, error) {
+	tmpl, err := template.New("tag").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTagFormat, err)
+	}
+
+	tagFormat := &TagFormat{template: tmpl}
+	rendered, err := tagFormat.render("module", versionSentinel)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Count(rendered, versionSentinel) != 1 {
+		return nil, fmt.Errorf("%w: %s has to contain {{.Version}} exactly once", ErrInvalidTagFormat, format)
+	}
+
+	return tagFormat, nil
+}
+
+func (format TagFormat) render(module string, version string) (string, error) {
+	var sb strings.Builder
+	if err := format.template.Execute(&sb, tagFormatData{Module: module, Version: version}); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTagFormat, err)
+	}
+	return sb.String(), nil
+}
+
+// Name returns the name of the Tag for a version of a Module.
+func (format TagFormat) Name(module string, version string) (string, error) {
+	return format.render(module, strings.TrimPrefix(version, "v"))
+}
+
+// Version parses the name of a Tag with the same template, which was used to name it.
+// It returns the version with a leading "v" and false, if the Tag does not belong to the Module.
+func (format TagFormat) Version(module string, name string) (string, bool) {
+	rendered, err := format.render(module, versionSentinel)
+	if err != nil {
+		return "", false
+	}
+
+	before, after, _ := strings.Cut(rendered, versionSentinel)
+	pattern, err := regexp.Compile("^" + regexp.QuoteMeta(before) + versionPattern + regexp.QuoteMeta(after) + "$")
+	if err != nil {
+		return "", false
+	}
+
+	match := pattern.FindStringSubmatch(name)
+	if match == nil {
+		return "", false
+	}
+
+	return "v" + match[1], true
+}
+
+// TagFormats configure how Tags are named, globally and per Module.
+type TagFormats struct {
+	// Default is used for all Modules without a format of their own.
+	// If it is nil, Tags are named <module>/<version>.
+	Default *TagFormat
+	// Modules maps Modules to their own format.
+	Modules map[string]*TagFormat
+}
+
+func (formats TagFormats) forModule(module string) *TagFormat {
+	if format, ok := formats.Modules[module]; ok {
+		return format
+	}
+	return formats.Default
+}
+
+// name returns the name of the Tag for a version of a Module.
+func (formats TagFormats) name(module string, version string) (string, error) {
+	format := formats.forModule(module)
+	if format == nil {
+		return tagName(version, module), nil
+	}
+	return format.Name(module, version)
+}
+
+// moduleTag creates the Tag of a Module and determines its version, if the name belongs to the Module.
+func (formats TagFormats) moduleTag(module string, name string, hash string) (Tag, bool) {
+	format := formats.forModule(module)
+	if format == nil {
+		var prefix string
+		if module != "" {
+			prefix = modulePrefix(module)
+		}
+
+		version, found := strings.CutPrefix(name, prefix)
+		return Tag{Name: name, Hash: hash, Version: version}, found
+	}
+
+	version, ok := format.Version(module, name)
+	return Tag{Name: name, Hash: hash, Version: version}, ok
+}
@@ -0,0 +1,122 @@
+package monoreleaser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		module   string
+		version  string
+		expected string
+	}{
+		{name: "at", format: "{{.Module}}@v{{.Version}}", module: "api", version: "v1.2.3", expected: "api@v1.2.3"},
+		{name: "dash", format: "{{.Module}}-v{{.Version}}", module: "services/api", version: "v1.2.3", expected: "services/api-v1.2.3"},
+		{name: "without v", format: "{{.Module}}/{{.Version}}", module: "api", version: "v1.2.3", expected: "api/1.2.3"},
+		{name: "prerelease", format: "{{.Module}}@v{{.Version}}", module: "api", version: "v1.2.3-rc.1", expected: "api@v1.2.3-rc.1"},
+		{name: "root", format: "{{if .Module}}{{.Module}}@{{end}}v{{.Version}}", module: "", version: "1.2.3", expected: "v1.2.3"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			format, err := NewTagFormat(testCase.format)
+			require.NoError(t, err)
+
+			name, err := format.Name(testCase.module, testCase.version)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, name)
+
+			version, ok := format.Version(testCase.module, name)
+			assert.True(t, ok)
+			assert.Equal(t, "v"+strings.TrimPrefix(testCase.version, "v"), version)
+		})
+	}
+}
+
+func TestTagFormat_Version_OtherModule(t *testing.T) {
+	format, err := NewTagFormat("{{.Module}}-v{{.Version}}")
+	require.NoError(t, err)
+
+	_, ok := format.Version("api", "api-gateway-v1.0.0")
+	assert.False(t, ok)
+	_, ok = format.Version("api", "api-vnext")
+	assert.False(t, ok)
+}
+
+func TestNewTagFormat_Invalid(t *testing.T) {
+	for _, format := range []string{"{{.Module}}", "{{.Version}}-{{.Version}}", "{{.Module", "{{.Unknown}}{{.Version}}"} {
+		_, err := NewTagFormat(format)
+		assert.ErrorIs(t, err, ErrInvalidTagFormat, format)
+	}
+}
+
+// withTagFormats configures the TagFormats of any Repository implementation.
+func withTagFormats(repository Repository, formats TagFormats) Repository {
+	switch repo := repository.(type) {
+	case GoGitRepository:
+		return repo.WithTagFormats(formats)
+	case GitBinaryRepository:
+		return repo.WithTagFormats(formats)
+	default:
+		panic("unknown repository implementation")
+	}
+}
+
+func TestTagFormats(t *testing.T) {
+	defaultFormat, err := NewTagFormat("{{if .Module}}{{.Module}}@{{end}}v{{.Version}}")
+	require.NoError(t, err)
+	apiFormat, err := NewTagFormat("{{.Module}}-{{.Version}}")
+	require.NoError(t, err)
+	formats := TagFormats{
+		Default: defaultFormat,
+		Modules: map[string]*TagFormat{"api": apiFormat},
+	}
+
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(false, &commits), func(t *testing.T, repository Repository) {
+		repository = withTagFormats(repository, formats)
+
+		tag, err := repository.Tag("v1.0.0", TagOptions{Module: "subdir"})
+		require.NoError(t, err)
+		assert.Equal(t, "subdir@v1.0.0", tag.Name)
+		assert.Equal(t, "v1.0.0", tag.Version)
+
+		tag, err = repository.Tag("v1.10.0", TagOptions{Module: "subdir"})
+		require.NoError(t, err)
+		assert.Equal(t, "subdir@v1.10.0", tag.Name)
+
+		tag, err = repository.Tag("v2.0.0", TagOptions{Module: "subdir", TagModule: "api"})
+		require.NoError(t, err)
+		assert.Equal(t, "api-2.0.0", tag.Name)
+
+		tag, err = repository.GetTag("v2.0.0", GetTagOptions{Module: "api"})
+		require.NoError(t, err)
+		assert.Equal(t, "api-2.0.0", tag.Name)
+
+		subdirTags, err := repository.GetTags(GetTagOptions{Module: "subdir"})
+		require.NoError(t, err)
+		require.Len(t, subdirTags, 2)
+		assert.Equal(t, "subdir@v1.10.0", subdirTags[0].Name)
+		assert.Equal(t, "v1.10.0", subdirTags[0].Version)
+		assert.Equal(t, "subdir@v1.0.0", subdirTags[1].Name)
+
+		apiTags, err := repository.GetTags(GetTagOptions{Module: "api"})
+		require.NoError(t, err)
+		require.Len(t, apiTags, 1)
+		assert.Equal(t, "v2.0.0", apiTags[0].Version)
+
+		// the tags created by newRepo do not match the format of the root module
+		rootTags, err := repository.GetTags(GetTagOptions{})
+		require.NoError(t, err)
+		for _, tag := range rootTags {
+			assert.NotContains(t, tag.Name, "/")
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidVersion       = errors.New("invalid version")
+	ErrInvalidVersionPolicy = errors.New("invalid version policy")
+	// ErrVersionNotGreater is the cause of both ErrVersionExists and ErrVersionDowngrade.
+	ErrVersionNotGreater = errors.New("version is not greater than the latest tag")
+	ErrVersionExists     = errors.New("version has been released already")
+	ErrVersionDowngrade  = errors.New("version is lower than the latest tag")
+	ErrVersionSkipped    = errors.New("version skips more than the changes justify")
+)
+
+// InitialVersion is the version a Module is considered to have, before it was released for the first time.
+const InitialVersion = "v0.0.0"
+
+// NextVersion determines the version following latestVersion by the highest Semantic of the Changes.
+// Reverts and Changes of unknown Semantic result in a patch release.
+// If latestVersion is empty, the Module has never been released and InitialVersion is bumped.
+// The second return value is false, if there are no Changes to release.
+func NextVersion(latestVersion string, changes []Change) (string, bool, error) {
+	if len(changes) == 0 {
+		return "", false, nil
+	}
+
+	if latestVersion == "" {
+		latestVersion = InitialVersion
+	}
+
+	latest := Version{version: latestVersion}
+	major, minor, patch, err := latest.semVer()
+	if err != nil {
+		return "", false, err
+	}
+
+	prefix := ""
+	if strings.HasPrefix(latestVersion, "v") {
+		prefix = "v"
+	}
+
+	// the changes since a pre-release are released by the version it precedes, e.g. v1.0.0 follows v1.0.0-rc.1
+	if latest.prerelease() != "" {
+		return fmt.Sprintf("%s%d.%d.%d", prefix, major, minor, patch), true, nil
+	}
+
+	switch highestSemantic(changes) {
+	case Major:
+		major, minor, patch = major+1, 0, 0
+	case Minor:
+		minor, patch = minor+1, 0
+	default:
+		patch++
+	}
+
+	return fmt.Sprintf("%s%d.%d.%d", prefix, major, minor, patch), true, nil
+}
+
+func highestSemantic(changes []Change) Semantic {
+	highest := Patch
+	for _, change := range changes {
+		switch change.Semantic {
+		case Major:
+			return Major
+		case Minor:
+			highest = Minor
+		case Patch, Revert, Unknown:
+		}
+	}
+
+	return highest
+}
+
+// A VersionPolicy decides which versions a Module may be released with.
+type VersionPolicy string
+
+const (
+	// VersionPolicyLenient refuses invalid versions, versions which have been released already and downgrades.
+	// It is the default.
+	VersionPolicyLenient VersionPolicy = "lenient"
+	// VersionPolicyStrict additionally refuses versions, which skip more than the Changes since the latest Tag justify,
+	// e.g. a major release of fixes only or v1.2.5 following v1.2.3.
+	VersionPolicyStrict VersionPolicy = "strict"
+)
+
+// ParseVersionPolicy returns the VersionPolicy of the given name, where an empty name is VersionPolicyLenient.
+func ParseVersionPolicy(policy string) (VersionPolicy, error) {
+	switch VersionPolicy(policy) {
+	case "", VersionPolicyLenient:
+		return VersionPolicyLenient, nil
+	case VersionPolicyStrict:
+		return VersionPolicyStrict, nil
+	default:
+		return "", fmt.Errorf("%w: %s (expected lenient or strict)", ErrInvalidVersionPolicy, policy)
+	}
+}
+
+// semVerPattern matches the supported vMajor.Minor.Patch versions, where the "v" is optional
+// and missing minor and patch numbers are treated as 0 like Version does.
+// A pre-release like -rc.1 and build metadata like +build.5 may follow.
+var semVerPattern = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+){0,2}(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// IsPrerelease reports whether a version is a pre-release like v1.0.0-rc.1.
+func IsPrerelease(version string) bool {
+	return Version{version: version}.prerelease() != ""
+}
+
+// ValidateNextVersion ensures that a version is greater than the latest Tag of a Module, which is nil if it has never been released.
+func ValidateNextVersion(latestTag *Tag, version string) error {
+	if !semVerPattern.MatchString(version) {
+		return fmt.Errorf("%w: %s (expected vMajor.Minor.Patch)", ErrInvalidVersion, version)
+	}
+	if latestTag == nil {
+		return nil
+	}
+
+	greater, err := Version{version: version}.Gt(Version{version: latestTag.Version})
+	if err != nil {
+		return err
+	}
+	if greater {
+		return nil
+	}
+
+	if strings.TrimPrefix(version, "v") == strings.TrimPrefix(latestTag.Version, "v") {
+		return fmt.Errorf("%w: %w: %s", ErrVersionNotGreater, ErrVersionExists, latestTag.Name)
+	}
+	return fmt.Errorf("%w: %w: %s is lower than %s", ErrVersionNotGreater, ErrVersionDowngrade, version, latestTag.Name)
+}
+
+// SameVersion reports whether two versions are equal, regardless of their "v" prefix, as Tags do not need to have one.
+func SameVersion(version1 string, version2 string) bool {
+	return strings.TrimPrefix(version1, "v") == strings.TrimPrefix(version2, "v")
+}
+
+// ValidateVersion validates a version against the Tags of a Module, which are ordered from the latest to the oldest,
+// and against the Changes since the latest Tag according to the VersionPolicy.
+// The first release of a Module may have any valid version.
+func ValidateVersion(tags []Tag, version string, changes []Change, policy VersionPolicy) error {
+	if !semVerPattern.MatchString(version) {
+		return fmt.Errorf("%w: %s (expected vMajor.Minor.Patch)", ErrInvalidVersion, version)
+	}
+
+	// older versions are taken, even if they are lower than the latest Tag, e.g. by a hotfix
+	for _, tag := range tags {
+		if SameVersion(tag.Version, version) {
+			return fmt.Errorf("%w: %w: %s", ErrVersionNotGreater, ErrVersionExists, tag.Name)
+		}
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+	latestTag := &tags[0]
+	if err := ValidateNextVersion(latestTag, version); err != nil {
+		return err
+	}
+
+	if policy != VersionPolicyStrict {
+		return nil
+	}
+
+	justified, changed, err := NextVersion(latestTag.Version, changes)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("%w: there are no changes since %s", ErrVersionSkipped, latestTag.Name)
+	}
+
+	skipped, err := Version{version: version}.Gt(Version{version: justified})
+	if err != nil {
+		return err
+	}
+	if skipped {
+		return fmt.Errorf("%w: %s follows %s, which justifies %s at most", ErrVersionSkipped, version, latestTag.Name, justified)
+	}
+
+	return nil
+}
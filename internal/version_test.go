@@ -0,0 +1,137 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextVersion(t *testing.T) {
+	testCases := []struct {
+		name          string
+		latestVersion string
+		semantics     []Semantic
+		expected      string
+		changed       bool
+	}{
+		{name: "major", latestVersion: "v1.2.3", semantics: []Semantic{Patch, Major, Minor}, expected: "v2.0.0", changed: true},
+		{name: "minor", latestVersion: "v1.2.3", semantics: []Semantic{Patch, Minor}, expected: "v1.3.0", changed: true},
+		{name: "patch", latestVersion: "v1.2.3", semantics: []Semantic{Patch}, expected: "v1.2.4", changed: true},
+		{name: "revert", latestVersion: "v1.2.3", semantics: []Semantic{Revert}, expected: "v1.2.4", changed: true},
+		{name: "unknown", latestVersion: "v1.2.3", semantics: []Semantic{Unknown}, expected: "v1.2.4", changed: true},
+		{name: "short version", latestVersion: "v1", semantics: []Semantic{Minor}, expected: "v1.1.0", changed: true},
+		{name: "without prefix", latestVersion: "1.2.3", semantics: []Semantic{Patch}, expected: "1.2.4", changed: true},
+		{name: "never released", latestVersion: "", semantics: []Semantic{Minor}, expected: "v0.1.0", changed: true},
+		{name: "no changes", latestVersion: "v1.2.3", semantics: nil, expected: "", changed: false},
+		{name: "prerelease", latestVersion: "v2.0.0-rc.1", semantics: []Semantic{Minor}, expected: "v2.0.0", changed: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			var changes []Change
+			for _, semantic := range testCase.semantics {
+				changes = append(changes, Change{Semantic: semantic})
+			}
+
+			version, changed, err := NextVersion(testCase.latestVersion, changes)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, version)
+			assert.Equal(t, testCase.changed, changed)
+		})
+	}
+}
+
+func TestNextVersion_Invalid(t *testing.T) {
+	_, _, err := NextVersion("latest", []Change{{Semantic: Patch}})
+	assert.Error(t, err)
+}
+
+func TestParseVersionPolicy(t *testing.T) {
+	for policy, expected := range map[string]VersionPolicy{"": VersionPolicyLenient, "lenient": VersionPolicyLenient, "strict": VersionPolicyStrict} {
+		parsed, err := ParseVersionPolicy(policy)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, parsed)
+	}
+
+	_, err := ParseVersionPolicy("yolo")
+	assert.ErrorIs(t, err, ErrInvalidVersionPolicy)
+}
+
+func TestValidateNextVersion(t *testing.T) {
+	latestTag := &Tag{Name: "subdir/v2.3.0", Version: "v2.3.0"}
+
+	assert.NoError(t, ValidateNextVersion(nil, "v0.0.1"))
+	assert.NoError(t, ValidateNextVersion(latestTag, "v2.3.1"))
+	assert.ErrorIs(t, ValidateNextVersion(latestTag, "v2.3.0"), ErrVersionExists)
+	assert.ErrorIs(t, ValidateNextVersion(latestTag, "v0.0.1"), ErrVersionDowngrade)
+	assert.ErrorIs(t, ValidateNextVersion(latestTag, "v0.0.1"), ErrVersionNotGreater)
+	assert.ErrorIs(t, ValidateNextVersion(latestTag, "latest"), ErrInvalidVersion)
+	assert.NoError(t, ValidateNextVersion(latestTag, "v2.4.0-rc.1"))
+	assert.ErrorIs(t, ValidateNextVersion(latestTag, "v2.3.0-rc.1"), ErrVersionDowngrade)
+	assert.NoError(t, ValidateNextVersion(&Tag{Name: "v2.4.0-rc.1", Version: "v2.4.0-rc.1"}, "v2.4.0"))
+	assert.NoError(t, ValidateNextVersion(&Tag{Name: "v2.4.0-rc.1", Version: "v2.4.0-rc.1"}, "v2.4.0-rc.2"))
+}
+
+func TestIsPrerelease(t *testing.T) {
+	assert.True(t, IsPrerelease("v1.0.0-rc.1"))
+	assert.True(t, IsPrerelease("1.0.0-alpha+build.5"))
+	assert.False(t, IsPrerelease("v1.0.0"))
+	assert.False(t, IsPrerelease("v1.0.0+build-5"))
+}
+
+func TestValidateVersion(t *testing.T) {
+	tags := []Tag{
+		{Name: "v2.3.0", Version: "v2.3.0"},
+		{Name: "v2.2.1", Version: "v2.2.1"},
+		{Name: "v2.2.0", Version: "v2.2.0"},
+	}
+	fixes := []Change{{Semantic: Patch}}
+	features := []Change{{Semantic: Patch}, {Semantic: Minor}}
+
+	testCases := []struct {
+		name     string
+		tags     []Tag
+		version  string
+		changes  []Change
+		policy   VersionPolicy
+		expected error
+	}{
+		{name: "patch", tags: tags, version: "v2.3.1", changes: fixes, policy: VersionPolicyStrict},
+		{name: "short version", tags: tags, version: "v3", changes: fixes, policy: VersionPolicyLenient},
+		{name: "without prefix", tags: tags, version: "2.4.0", changes: features, policy: VersionPolicyStrict},
+		{name: "lower bump than justified", tags: tags, version: "v2.3.1", changes: features, policy: VersionPolicyStrict},
+		{name: "first release", tags: nil, version: "v5.0.0", changes: fixes, policy: VersionPolicyStrict},
+		{name: "invalid", tags: tags, version: "latest", policy: VersionPolicyLenient, expected: ErrInvalidVersion},
+		{name: "too many numbers", tags: tags, version: "v1.2.3.4", policy: VersionPolicyLenient, expected: ErrInvalidVersion},
+		{name: "duplicate", tags: tags, version: "v2.3.0", policy: VersionPolicyLenient, expected: ErrVersionExists},
+		{name: "older duplicate", tags: tags, version: "2.2.1", policy: VersionPolicyLenient, expected: ErrVersionExists},
+		{name: "downgrade", tags: tags, version: "v0.0.1", policy: VersionPolicyLenient, expected: ErrVersionDowngrade},
+		{name: "lenient skip", tags: tags, version: "v4.0.0", changes: fixes, policy: VersionPolicyLenient},
+		{name: "major of fixes", tags: tags, version: "v3.0.0", changes: fixes, policy: VersionPolicyStrict, expected: ErrVersionSkipped},
+		{name: "skipped patch", tags: tags, version: "v2.3.2", changes: fixes, policy: VersionPolicyStrict, expected: ErrVersionSkipped},
+		{name: "no changes", tags: tags, version: "v2.3.1", policy: VersionPolicyStrict, expected: ErrVersionSkipped},
+		{name: "prerelease", tags: tags, version: "v2.4.0-rc.1", changes: features, policy: VersionPolicyLenient},
+		{name: "build metadata", tags: tags, version: "v2.3.1+build.5", changes: fixes, policy: VersionPolicyLenient},
+		{name: "invalid prerelease", tags: tags, version: "v2.4.0-", policy: VersionPolicyLenient, expected: ErrInvalidVersion},
+		{
+			name:    "release of prerelease",
+			tags:    append([]Tag{{Name: "v2.4.0-rc.1", Version: "v2.4.0-rc.1"}}, tags...),
+			version: "v2.4.0",
+			changes: features,
+			policy:  VersionPolicyStrict,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateVersion(testCase.tags, testCase.version, testCase.changes, testCase.policy)
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, testCase.expected)
+		})
+	}
+}
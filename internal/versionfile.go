@@ -0,0 +1,453 @@
+package monoreleaser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnsupportedVersionFile = errors.New("no version updater for file")
+	ErrVersionNotFound        = errors.New("version not found in file")
+)
+
+// A VersionUpdater writes a version into the content of a file, keeping the rest of it untouched.
+type VersionUpdater interface {
+	// Update returns the content with the version written into it.
+	// The version has no leading "v", as most package managers do not accept it.
+	Update(content []byte, version string) ([]byte, error)
+}
+
+// VersionUpdaterFunc is a function, which is used as a VersionUpdater.
+type VersionUpdaterFunc func(content []byte, version string) ([]byte, error)
+
+var _ VersionUpdater = VersionUpdaterFunc(nil)
+
+func (update VersionUpdaterFunc) Update(content []byte, version string) ([]byte, error) {
+	return update(content, version)
+}
+
+// VersionUpdaters maps file names to the VersionUpdater writing the version into them.
+// Register an updater to support further files.
+var VersionUpdaters = map[string]VersionUpdater{
+	"package.json":   VersionUpdaterFunc(updatePackageJSON),
+	"Cargo.toml":     VersionUpdaterFunc(updateCargoToml),
+	"pyproject.toml": VersionUpdaterFunc(updatePyprojectToml),
+	"Chart.yaml":     VersionUpdaterFunc(updateChartYaml),
+	"pom.xml":        VersionUpdaterFunc(updatePomXML),
+	"VERSION":        VersionUpdaterFunc(updateVersionFile),
+}
+
+// A VersionReader reads the version a file declares, e.g. to release the version maintained in it.
+type VersionReader interface {
+	// Read returns the version without a leading "v", or ErrVersionNotFound.
+	Read(content []byte) (string, error)
+}
+
+// VersionReaderFunc is a function, which is used as a VersionReader.
+type VersionReaderFunc func(content []byte) (string, error)
+
+var _ VersionReader = VersionReaderFunc(nil)
+
+func (read VersionReaderFunc) Read(content []byte) (string, error) {
+	return read(content)
+}
+
+// VersionReaders maps file names to the VersionReader reading the version out of them.
+// Register a reader to support further files.
+var VersionReaders = map[string]VersionReader{
+	"package.json":   VersionReaderFunc(readPackageJSONVersion),
+	"Cargo.toml":     VersionReaderFunc(readCargoTomlVersion),
+	"pyproject.toml": VersionReaderFunc(readPyprojectTomlVersion),
+	"Chart.yaml":     VersionReaderFunc(readChartYamlVersion),
+	"pom.xml":        VersionReaderFunc(readPomXMLVersion),
+	"VERSION":        VersionReaderFunc(readVersionFile),
+}
+
+func readPackageJSONVersion(content []byte) (string, error) {
+	var packageJSON struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(content, &packageJSON); err != nil {
+		return "", err
+	}
+	return nonEmptyVersion(packageJSON.Version)
+}
+
+func readCargoTomlVersion(content []byte) (string, error) {
+	return readTOMLVersion(content, "package")
+}
+
+func readPyprojectTomlVersion(content []byte) (string, error) {
+	return readTOMLVersion(content, "project", "tool.poetry")
+}
+
+func readTOMLVersion(content []byte, tables ...string) (string, error) {
+	_, match := findTOMLVersion(splitLines(content), tables)
+	if match == nil {
+		return "", ErrVersionNotFound
+	}
+	return nonEmptyVersion(match[3])
+}
+
+func readChartYamlVersion(content []byte) (string, error) {
+	var metadata ChartMetadata
+	if err := yaml.Unmarshal(content, &metadata); err != nil {
+		return "", err
+	}
+	return nonEmptyVersion(metadata.Version)
+}
+
+func readPomXMLVersion(content []byte) (string, error) {
+	var pom struct {
+		XMLName xml.Name `xml:"project"`
+		Version string   `xml:"version"`
+	}
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return "", err
+	}
+	return nonEmptyVersion(strings.TrimSpace(pom.Version))
+}
+
+func readVersionFile(content []byte) (string, error) {
+	return nonEmptyVersion(strings.TrimSpace(string(content)))
+}
+
+func nonEmptyVersion(version string) (string, error) {
+	if version == "" {
+		return "", ErrVersionNotFound
+	}
+	return version, nil
+}
+
+// updatePackageJSON replaces the top level version of a package.json.
+func updatePackageJSON(content []byte, version string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	depth := 0
+	expectKey := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrVersionNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case json.Delim:
+			if token == '{' || token == '[' {
+				depth++
+			} else {
+				depth--
+			}
+			expectKey = depth == 1 && token != '['
+			continue
+		case string:
+			if depth == 1 && expectKey && token == "version" {
+				keyEnd := int(decoder.InputOffset())
+				if _, err := decoder.Token(); err != nil {
+					return nil, err
+				}
+				valueEnd := int(decoder.InputOffset())
+				valueStart := keyEnd + bytes.IndexByte(content[keyEnd:valueEnd], '"')
+				if valueStart < keyEnd {
+					return nil, ErrVersionNotFound
+				}
+
+				quoted, err := json.Marshal(version)
+				if err != nil {
+					return nil, err
+				}
+				return replaceRange(content, valueStart, valueEnd, quoted), nil
+			}
+		}
+
+		// keys and values alternate inside of the top level object
+		if depth == 1 {
+			expectKey = !expectKey
+		}
+	}
+}
+
+// tomlVersionPattern matches a version assignment like version = "1.2.3" in a TOML file.
+var tomlVersionPattern = regexp.MustCompile(`^(\s*version\s*=\s*)(["'])([^"']*)(["'].*)$`)
+
+func updateCargoToml(content []byte, version string) ([]byte, error) {
+	return updateTOMLVersion(content, version, "package")
+}
+
+func updatePyprojectToml(content []byte, version string) ([]byte, error) {
+	return updateTOMLVersion(content, version, "project", "tool.poetry")
+}
+
+// updateTOMLVersion replaces the version inside of the first of the given tables of a TOML file.
+func updateTOMLVersion(content []byte, version string, tables ...string) ([]byte, error) {
+	lines := splitLines(content)
+	i, match := findTOMLVersion(lines, tables)
+	if match == nil {
+		return nil, ErrVersionNotFound
+	}
+
+	_, ending := cutLineEnding(lines[i])
+	lines[i] = match[1] + match[2] + version + match[4] + ending
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// findTOMLVersion returns the line of the version inside of the first of the given tables and its tomlVersionPattern match,
+// which is nil if there is no version.
+func findTOMLVersion(lines []string, tables []string) (int, []string) {
+	var table string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			table = strings.TrimSpace(strings.Trim(trimmed, "[]"))
+			continue
+		}
+
+		if !contains(tables, table) {
+			continue
+		}
+		body, _ := cutLineEnding(line)
+		if match := tomlVersionPattern.FindStringSubmatch(body); match != nil {
+			return i, match
+		}
+	}
+
+	return 0, nil
+}
+
+// chartVersionPattern matches a top level version or appVersion of a Chart.yaml.
+var chartVersionPattern = regexp.MustCompile(`^((?:version|appVersion):\s*)(["']?)[^"'\s#]*(["']?)(.*)$`)
+
+// updateChartYaml replaces the version and, if there is one, the appVersion of a Chart.yaml.
+func updateChartYaml(content []byte, version string) ([]byte, error) {
+	lines := splitLines(content)
+	found := false
+	for i, line := range lines {
+		body, ending := cutLineEnding(line)
+		match := chartVersionPattern.FindStringSubmatch(body)
+		if match == nil {
+			continue
+		}
+		lines[i] = match[1] + match[2] + version + match[3] + match[4] + ending
+		if strings.HasPrefix(line, "version") {
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, ErrVersionNotFound
+	}
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// updatePomXML replaces the version of the project itself, but not the one of its parent or dependencies.
+func updatePomXML(content []byte, version string) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var elements []string
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrVersionNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			elements = append(elements, token.Name.Local)
+			if strings.Join(elements, "/") != "project/version" {
+				continue
+			}
+
+			start := int(decoder.InputOffset())
+			// a self-closing <version/> has no content, which the version could replace
+			if bytes.HasSuffix(content[:start], []byte("/>")) {
+				return nil, ErrVersionNotFound
+			}
+			end := start
+			next, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := next.(xml.CharData); ok {
+				end = int(decoder.InputOffset())
+			}
+
+			var escaped bytes.Buffer
+			if err := xml.EscapeText(&escaped, []byte(version)); err != nil {
+				return nil, err
+			}
+			return replaceRange(content, start, end, escaped.Bytes()), nil
+		case xml.EndElement:
+			elements = elements[:len(elements)-1]
+		}
+	}
+}
+
+// updateVersionFile replaces the whole content of a plain VERSION file.
+func updateVersionFile(_ []byte, version string) ([]byte, error) {
+	return []byte(version + "\n"), nil
+}
+
+func replaceRange(content []byte, start int, end int, replacement []byte) []byte {
+	updated := make([]byte, 0, len(content)-(end-start)+len(replacement))
+	updated = append(updated, content[:start]...)
+	updated = append(updated, replacement...)
+	return append(updated, content[end:]...)
+}
+
+// splitLines splits the content into lines, which keep their line endings.
+func splitLines(content []byte) []string {
+	var lines []string
+	reader := bufio.NewReader(bytes.NewReader(content))
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			lines = append(lines, line)
+		}
+		if err != nil {
+			return lines
+		}
+	}
+}
+
+// cutLineEnding splits a line into its content and its line ending.
+func cutLineEnding(line string) (string, string) {
+	body := strings.TrimRight(line, "\r\n")
+	return body, line[len(body):]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultVersionCommitMessage is the message of Commits bumping the version files of a Module.
+const DefaultVersionCommitMessage = "chore(release): {{.Module}} {{.Version}}"
+
+// Optional parameters for bumping the version files of a Module.
+type BumpOptions struct {
+	// Root is the directory of the repository.
+	// If this option is not set, the current directory will be used.
+	Root string
+	// Message is a text/template of the Commit message, which is executed with the Module and the Version.
+	// If this option is not set, DefaultVersionCommitMessage will be used.
+	Message string
+}
+
+type versionCommitData struct {
+	Module  string
+	Version string
+}
+
+// BumpVersionFiles writes the version into the version files of a Module and commits them.
+// It returns the created Commit, which is meant to be tagged, or nil if no file has changed.
+func BumpVersionFiles(
+	fsys afero.Fs,
+	repository Repository,
+	module Module,
+	version string,
+	opts BumpOptions,
+) (*Commit, error) {
+	if opts.Root == "" {
+		opts.Root = "."
+	}
+	// an invalid template fails before any version file is written
+	message, err := versionCommitMessage(opts.Message, module, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// all version files are updated before any of them is written, so that a failing one does not leave the others bumped
+	type versionFile struct {
+		path    string
+		osPath  string
+		content []byte
+		mode    fs.FileMode
+	}
+	var updates []versionFile
+	for _, file := range module.VersionFiles {
+		updater, ok := VersionUpdaters[path.Base(file)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersionFile, file)
+		}
+
+		filePath := path.Join(module.Path, file)
+		osPath := filepath.Join(opts.Root, filepath.FromSlash(filePath))
+		content, err := afero.ReadFile(fsys, osPath)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := updater.Update(content, strings.TrimPrefix(version, "v"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filePath, err)
+		}
+		if bytes.Equal(content, updated) {
+			continue
+		}
+
+		info, err := fsys.Stat(osPath)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, versionFile{path: filePath, osPath: osPath, content: updated, mode: info.Mode()})
+	}
+
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	changed := make([]string, 0, len(updates))
+	for _, update := range updates {
+		if err := afero.WriteFile(fsys, update.osPath, update.content, update.mode); err != nil {
+			return nil, err
+		}
+		changed = append(changed, update.path)
+	}
+
+	return repository.Commit(message, CommitOptions{Paths: changed})
+}
+
+// versionCommitMessage executes the template of the message of the Commit bumping the version files of a Module.
+// If the template is empty, DefaultVersionCommitMessage will be used.
+func versionCommitMessage(text string, module Module, version string) (string, error) {
+	if text == "" {
+		text = DefaultVersionCommitMessage
+	}
+
+	message, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	moduleLabel := module.Name
+	if moduleLabel == "" {
+		moduleLabel = ModuleName(module.Path)
+	}
+	var sb strings.Builder
+	if err := message.Execute(&sb, versionCommitData{Module: moduleLabel, Version: version}); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
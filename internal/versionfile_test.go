@@ -0,0 +1,237 @@
+package monoreleaser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionUpdaters(t *testing.T) {
+	testCases := []struct {
+		file     string
+		content  string
+		expected string
+	}{
+		{
+			file: "package.json",
+			content: `{
+  "name": "web",
+  "dependencies": {"version": "1.0.0"},
+  "scripts": ["version"],
+  "version": "1.2.3",
+  "private": true
+}
+`,
+			expected: `{
+  "name": "web",
+  "dependencies": {"version": "1.0.0"},
+  "scripts": ["version"],
+  "version": "2.0.0",
+  "private": true
+}
+`,
+		},
+		{
+			file: "Cargo.toml",
+			content: `[dependencies]
+serde = { version = "1.0" }
+
+[package]
+name = "api"
+version = "1.2.3" # managed by monoreleaser
+
+[dev-dependencies]
+version = "1.0"
+`,
+			expected: `[dependencies]
+serde = { version = "1.0" }
+
+[package]
+name = "api"
+version = "2.0.0" # managed by monoreleaser
+
+[dev-dependencies]
+version = "1.0"
+`,
+		},
+		{
+			file:     "pyproject.toml",
+			content:  "[tool.poetry]\r\nname = 'cli'\r\nversion = '1.2.3'\r\n",
+			expected: "[tool.poetry]\r\nname = 'cli'\r\nversion = '2.0.0'\r\n",
+		},
+		{
+			file: "Chart.yaml",
+			content: `apiVersion: v2
+name: api
+version: 1.2.3
+appVersion: "1.2.3"
+dependencies:
+  - name: redis
+    version: 17.0.0
+`,
+			expected: `apiVersion: v2
+name: api
+version: 2.0.0
+appVersion: "2.0.0"
+dependencies:
+  - name: redis
+    version: 17.0.0
+`,
+		},
+		{
+			file: "pom.xml",
+			content: `<project>
+  <parent><version>3.0.0</version></parent>
+  <artifactId>api</artifactId>
+  <version>1.2.3</version>
+  <dependencies><dependency><version>1.0</version></dependency></dependencies>
+</project>
+`,
+			expected: `<project>
+  <parent><version>3.0.0</version></parent>
+  <artifactId>api</artifactId>
+  <version>2.0.0</version>
+  <dependencies><dependency><version>1.0</version></dependency></dependencies>
+</project>
+`,
+		},
+		{
+			file:     "VERSION",
+			content:  "1.2.3\n",
+			expected: "2.0.0\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.file, func(t *testing.T) {
+			updated, err := VersionUpdaters[testCase.file].Update([]byte(testCase.content), "2.0.0")
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, string(updated))
+		})
+	}
+}
+
+func TestVersionUpdaters_VersionNotFound(t *testing.T) {
+	testCases := []struct {
+		file    string
+		content string
+	}{
+		{file: "package.json", content: `{"name": "web", "dependencies": {"version": "1.0.0"}}`},
+		{file: "Cargo.toml", content: "[package]\nname = \"api\"\nversion.workspace = true\n"},
+		{file: "pyproject.toml", content: "[tool.black]\nversion = '1.0'\n"},
+		{file: "Chart.yaml", content: "name: api\nappVersion: 1.2.3\n"},
+		{file: "pom.xml", content: "<project><parent><version>3.0.0</version></parent></project>"},
+		{file: "pom.xml", content: "<project><version/></project>"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := VersionUpdaters[testCase.file].Update([]byte(testCase.content), "2.0.0")
+		assert.ErrorIs(t, err, ErrVersionNotFound, testCase.content)
+	}
+}
+
+// versionFileRepo returns a setup function for forEachImplementation, which creates a new repository with a committer
+// and a package.json in subdir.
+func versionFileRepo(t *testing.T) string {
+	dir := t.TempDir()
+	newRepo(dir, false)
+
+	gitRepository, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	config, err := gitRepository.Config()
+	require.NoError(t, err)
+	config.User.Name = "orca"
+	config.User.Email = "orca-dev@mail.com"
+	require.NoError(t, gitRepository.SetConfig(config))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "subdir", "package.json"), []byte(`{"version": "1.0.0"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "untracked"), []byte{}, 0o644))
+	return dir
+}
+
+func TestBumpVersionFiles(t *testing.T) {
+	var dir string
+	forEachImplementation(t, func(t *testing.T) string {
+		dir = versionFileRepo(t)
+		return dir
+	}, func(t *testing.T, repository Repository) {
+		module := NewConfiguredModule("web", ModulePaths{Include: []string{"subdir"}})
+		module.VersionFiles = []string{"package.json"}
+
+		commit, err := BumpVersionFiles(afero.NewOsFs(), repository, module, "v1.1.0", BumpOptions{Root: dir})
+		require.NoError(t, err)
+		require.NotNil(t, commit)
+		assert.Equal(t, "chore(release): web v1.1.0", commit.Message)
+
+		head, err := repository.Head()
+		require.NoError(t, err)
+		assert.Equal(t, head, commit.Hash)
+
+		content, err := os.ReadFile(filepath.Join(dir, "subdir", "package.json"))
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": "1.1.0"}`, string(content))
+
+		history, err := repository.History(HistoryOptions{Module: "subdir"})
+		require.NoError(t, err)
+		latest, err := history.Next()
+		require.NoError(t, err)
+		assert.Equal(t, commit.Hash, latest.Hash)
+		history.Close()
+
+		// untracked files are not part of the Commit
+		history, err = repository.History(HistoryOptions{Paths: ModulePaths{Include: []string{"untracked"}}})
+		require.NoError(t, err)
+		_, err = history.Next()
+		assert.ErrorIs(t, err, ErrEndOfHistory)
+
+		// nothing to commit, once the version has been written
+		commit, err = BumpVersionFiles(afero.NewOsFs(), repository, module, "v1.1.0", BumpOptions{Root: dir})
+		assert.NoError(t, err)
+		assert.Nil(t, commit)
+	})
+}
+
+func TestBumpVersionFiles_Message(t *testing.T) {
+	var dir string
+	forEachImplementation(t, func(t *testing.T) string {
+		dir = versionFileRepo(t)
+		return dir
+	}, func(t *testing.T, repository Repository) {
+		module := Module{Path: "subdir", VersionFiles: []string{"package.json"}}
+
+		commit, err := BumpVersionFiles(afero.NewOsFs(), repository, module, "v1.1.0", BumpOptions{
+			Root:    dir,
+			Message: "release {{.Module}}@{{.Version}} [skip ci]",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "release subdir@v1.1.0 [skip ci]", commit.Message)
+	})
+}
+
+func TestBumpVersionFiles_Unsupported(t *testing.T) {
+	module := Module{Path: "subdir", VersionFiles: []string{"build.gradle"}}
+	_, err := BumpVersionFiles(afero.NewMemMapFs(), repository, module, "v1.1.0", BumpOptions{})
+	assert.ErrorIs(t, err, ErrUnsupportedVersionFile)
+}
+
+func TestBumpVersionFiles_VersionNotFound(t *testing.T) {
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fsys, "subdir/package.json", []byte(`{"version": "1.0.0"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "subdir/pom.xml", []byte("<project><version/></project>"), 0o644))
+
+	module := Module{Path: "subdir", VersionFiles: []string{"package.json", "pom.xml"}}
+	commit, err := BumpVersionFiles(fsys, repository, module, "v1.1.0", BumpOptions{})
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	assert.Nil(t, commit)
+
+	// the version files are bumped all together or not at all
+	content, err := afero.ReadFile(fsys, "subdir/package.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"version": "1.0.0"}`, string(content))
+}
@@ -0,0 +1,111 @@
+package monoreleaser
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+var ErrInvalidVersionSource = errors.New("invalid version source")
+
+// A VersionSource is the source of truth of the version a Module is released with.
+type VersionSource string
+
+const (
+	// VersionSourceTags releases the version passed on the command line, which has to follow the latest Tag.
+	// It is the default and derives the version from the Commits, when every Module is released at once.
+	VersionSourceTags VersionSource = "tags"
+	// VersionSourceFile releases the version maintained in the first version file of a Module, once it has been raised.
+	VersionSourceFile VersionSource = "file"
+	// VersionSourceCommits derives the version from the Changes since the latest Tag.
+	VersionSourceCommits VersionSource = "commits"
+)
+
+// ParseVersionSource returns the VersionSource of the given name, where an empty name is VersionSourceTags.
+func ParseVersionSource(source string) (VersionSource, error) {
+	switch VersionSource(source) {
+	case "", VersionSourceTags:
+		return VersionSourceTags, nil
+	case VersionSourceFile, VersionSourceCommits:
+		return VersionSource(source), nil
+	default:
+		return "", fmt.Errorf("%w: %s (expected tags, file or commits)", ErrInvalidVersionSource, source)
+	}
+}
+
+// ReadVersionFile reads the version maintained in the first version file of a Module.
+// The version is returned with a leading "v" like the version of a Tag.
+func ReadVersionFile(fsys afero.Fs, root string, module Module) (string, error) {
+	if len(module.VersionFiles) == 0 {
+		return "", fmt.Errorf("%w: %s has no version file", ErrInvalidVersionSource, ModuleName(module.Path))
+	}
+
+	file := module.VersionFiles[0]
+	reader, ok := VersionReaders[path.Base(file)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedVersionFile, file)
+	}
+
+	filePath := path.Join(module.Path, file)
+	content, err := afero.ReadFile(fsys, filepath.Join(root, filepath.FromSlash(filePath)))
+	if err != nil {
+		return "", err
+	}
+
+	version, err := reader.Read(content)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	return "v" + strings.TrimPrefix(version, "v"), nil
+}
+
+// PlanFileReleases determines the Modules with VersionSourceFile, whose version file declares a version greater than their latest Tag.
+// Modules whose version file still declares the version of the latest Tag are skipped, the others keep the order of the given Modules.
+func PlanFileReleases(
+	fsys afero.Fs,
+	root string,
+	repository Repository,
+	modules []Module,
+	opts DiffOptions,
+) ([]PlannedRelease, error) {
+	var plan []PlannedRelease
+	for _, module := range modules {
+		if module.VersionSource != VersionSourceFile {
+			continue
+		}
+
+		version, err := ReadVersionFile(fsys, root, module)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ModuleName(module.Path), err)
+		}
+
+		latestTag, commits, err := Unreleased(repository, module, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ModuleName(module.Path), err)
+		}
+
+		if latestTag != nil && SameVersion(latestTag.Version, version) {
+			continue
+		}
+		if err := ValidateNextVersion(latestTag, version); err != nil {
+			return nil, fmt.Errorf("%s: %w", ModuleName(module.Path), err)
+		}
+
+		plan = append(plan, PlannedRelease{
+			Module:    module.Path,
+			TagModule: module.TagModule,
+			Name:      module.Name,
+			Paths:     module.Paths,
+			LatestTag: latestTag,
+			Version:   version,
+			Changes:   Extract(commits),
+		})
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,122 @@
+package monoreleaser
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersionSource(t *testing.T) {
+	testCases := []struct {
+		source   string
+		expected VersionSource
+	}{
+		{source: "", expected: VersionSourceTags},
+		{source: "tags", expected: VersionSourceTags},
+		{source: "file", expected: VersionSourceFile},
+		{source: "commits", expected: VersionSourceCommits},
+	}
+
+	for _, tc := range testCases {
+		source, err := ParseVersionSource(tc.source)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, source)
+	}
+
+	_, err := ParseVersionSource("branch")
+	assert.ErrorIs(t, err, ErrInvalidVersionSource)
+}
+
+func TestVersionReaders(t *testing.T) {
+	testCases := []struct {
+		file    string
+		content string
+	}{
+		{file: "package.json", content: `{"dependencies": {"version": "1.0.0"}, "version": "1.2.3"}`},
+		{file: "Cargo.toml", content: "[dependencies]\nversion = \"1.0\"\n\n[package]\nversion = \"1.2.3\" # comment\n"},
+		{file: "pyproject.toml", content: "[tool.poetry]\r\nversion = '1.2.3'\r\n"},
+		{file: "Chart.yaml", content: "name: api\nversion: 1.2.3\nappVersion: \"1.0.0\"\n"},
+		{file: "pom.xml", content: "<project><parent><version>1.0.0</version></parent><version>1.2.3</version></project>"},
+		{file: "VERSION", content: "1.2.3\n"},
+	}
+
+	for _, tc := range testCases {
+		version, err := VersionReaders[tc.file].Read([]byte(tc.content))
+		require.NoError(t, err, tc.file)
+		assert.Equal(t, "1.2.3", version, tc.file)
+	}
+
+	for file, content := range map[string]string{
+		"package.json": `{"name": "web"}`,
+		"Cargo.toml":   "[dependencies]\nversion = \"1.0\"\n",
+		"Chart.yaml":   "name: api\n",
+		"pom.xml":      "<project><parent><version>1.0.0</version></parent></project>",
+		"VERSION":      "\n",
+	} {
+		_, err := VersionReaders[file].Read([]byte(content))
+		assert.ErrorIs(t, err, ErrVersionNotFound, file)
+	}
+}
+
+func TestReadVersionFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "repo/web/package.json", []byte(`{"version": "1.2.3"}`), 0o644))
+
+	version, err := ReadVersionFile(fs, "repo", Module{Path: "web", VersionFiles: []string{"package.json", "VERSION"}})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", version)
+
+	_, err = ReadVersionFile(fs, "repo", Module{Path: "web"})
+	assert.ErrorIs(t, err, ErrInvalidVersionSource)
+
+	_, err = ReadVersionFile(fs, "repo", Module{Path: "web", VersionFiles: []string{"build.gradle"}})
+	assert.ErrorIs(t, err, ErrUnsupportedVersionFile)
+}
+
+func TestPlanFileReleases(t *testing.T) {
+	forEachImplementation(t, sharedRepo, func(t *testing.T, repository Repository) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "VERSION", []byte("2.0.0\n"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "subdir/VERSION", []byte("1.11.0\n"), 0o644))
+
+		modules := []Module{
+			{Path: "", VersionFiles: []string{"VERSION"}, VersionSource: VersionSourceFile},
+			{Path: "subdir", VersionFiles: []string{"VERSION"}, VersionSource: VersionSourceFile},
+			{Path: "unknown", VersionSource: VersionSourceCommits},
+		}
+		plan, err := PlanFileReleases(fs, "", repository, modules, DiffOptions{})
+		require.NoError(t, err)
+		require.Len(t, plan, 1)
+		assert.Equal(t, "", plan[0].Module)
+		assert.Equal(t, "v1.10.0", plan[0].LatestTag.Name)
+		assert.Equal(t, "v2.0.0", plan[0].Version)
+
+		// file sourced Modules are not planned by their commits
+		plan, err = PlanReleases(repository, modules[:2], DiffOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, plan)
+
+		require.NoError(t, afero.WriteFile(fs, "subdir/VERSION", []byte("1.0.0\n"), 0o644))
+		_, err = PlanFileReleases(fs, "", repository, modules, DiffOptions{})
+		assert.ErrorIs(t, err, ErrVersionNotGreater)
+	})
+}
+
+func TestPlanFileReleases_TagWithoutPrefix(t *testing.T) {
+	var commits []*Commit
+	forEachImplementation(t, freshRepo(false, &commits), func(t *testing.T, repository Repository) {
+		_, err := repository.Tag("1.12.0", TagOptions{Module: "subdir"})
+		require.NoError(t, err)
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "subdir/VERSION", []byte("1.12.0\n"), 0o644))
+		modules := []Module{{Path: "subdir", VersionFiles: []string{"VERSION"}, VersionSource: VersionSourceFile}}
+
+		// the unchanged version is not released again
+		plan, err := PlanFileReleases(fs, "", repository, modules, DiffOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, plan)
+	})
+}